@@ -0,0 +1,235 @@
+package skipset
+
+import (
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestSetAlgebra(t *testing.T) {
+	a, b := NewInt64(), NewInt64()
+	for _, v := range []int64{1, 2, 3, 5, 8} {
+		a.Add(v)
+	}
+	for _, v := range []int64{2, 3, 4, 8, 9} {
+		b.Add(v)
+	}
+	checkSet(t, UnionInt64(a, b), []int64{1, 2, 3, 4, 5, 8, 9})
+	checkSet(t, IntersectInt64(a, b), []int64{2, 3, 8})
+	checkSet(t, DifferenceInt64(a, b), []int64{1, 5})
+	checkSet(t, DifferenceInt64(b, a), []int64{4, 9})
+	checkSet(t, SymmetricDifferenceInt64(a, b), []int64{1, 4, 5, 9})
+
+	empty := NewInt64()
+	checkSet(t, UnionInt64(a, empty), []int64{1, 2, 3, 5, 8})
+	checkSet(t, IntersectInt64(empty, a), []int64{})
+	checkSet(t, DifferenceInt64(a, empty), []int64{1, 2, 3, 5, 8})
+	checkSet(t, SymmetricDifferenceInt64(empty, b), []int64{2, 3, 4, 8, 9})
+
+	// The results are regular skip sets.
+	u := UnionInt64(a, b)
+	if !u.Add(6) || u.Add(9) || !u.Remove(1) || !u.Contains(6) {
+		t.Fatal("invalid result set")
+	}
+	checkSet(t, u, []int64{2, 3, 4, 5, 6, 8, 9})
+
+	// Descending order and FuncSet.
+	sa, sb := NewStringDesc(), NewStringDesc()
+	fa, fb := NewFloat64Desc(), NewFloat64Desc()
+	for _, v := range []string{"a", "b", "c"} {
+		sa.Add(v)
+	}
+	for _, v := range []string{"b", "c", "d"} {
+		sb.Add(v)
+	}
+	for _, v := range []float64{0.5, 1, 2} {
+		fa.Add(v)
+	}
+	for _, v := range []float64{1, 2, 4} {
+		fb.Add(v)
+	}
+	checkSet(t, UnionStringDesc(sa, sb), []string{"d", "c", "b", "a"})
+	checkSet(t, IntersectFunc(fa, fb), []float64{2, 1})
+	checkSet(t, SymmetricDifferenceFunc(fa, fb), []float64{4, 0.5})
+
+	// Random sets against maps.
+	x, y := New[int](), New[int]()
+	for i := 0; i < 1000; i++ {
+		x.Add(int(fastrand.Uint32n(1000)))
+		y.Add(int(fastrand.Uint32n(1000)))
+	}
+	var union, inter, diff, sym []int
+	for i := 0; i < 1000; i++ {
+		inx, iny := x.Contains(i), y.Contains(i)
+		if inx || iny {
+			union = append(union, i)
+		}
+		if inx && iny {
+			inter = append(inter, i)
+		}
+		if inx && !iny {
+			diff = append(diff, i)
+		}
+		if inx != iny {
+			sym = append(sym, i)
+		}
+	}
+	checkSet(t, Union(x, y), union)
+	checkSet(t, Intersect(x, y), inter)
+	checkSet(t, Difference(x, y), diff)
+	checkSet(t, SymmetricDifference(x, y), sym)
+}
+
+func TestSetRelations(t *testing.T) {
+	a, b := OfInt64(1, 2, 3), OfInt64(1, 2, 3, 5)
+	if !IsSubsetInt64(a, b) || IsSubsetInt64(b, a) || !IsSupersetInt64(b, a) || IsSupersetInt64(a, b) {
+		t.Fatal("invalid proper subset")
+	}
+	if EqualInt64(a, b) || !EqualInt64(a, OfInt64(3, 2, 1)) || !IsSubsetInt64(a, a) || !IsSupersetInt64(a, a) {
+		t.Fatal("invalid equal sets")
+	}
+	// Same length, different values.
+	c := OfInt64(1, 2, 4)
+	if EqualInt64(a, c) || IsSubsetInt64(a, c) || IsSupersetInt64(a, c) {
+		t.Fatal("invalid sets of the same length")
+	}
+	if DisjointInt64(a, c) || !DisjointInt64(a, OfInt64(4, 6)) || !DisjointInt64(OfInt64(0, 4), a) {
+		t.Fatal("invalid Disjoint")
+	}
+
+	// Empty sets on either side.
+	empty := NewInt64()
+	if !IsSubsetInt64(empty, a) || IsSubsetInt64(a, empty) || !IsSupersetInt64(a, empty) || IsSupersetInt64(empty, a) {
+		t.Fatal("invalid empty subset")
+	}
+	if EqualInt64(empty, a) || EqualInt64(a, empty) || !EqualInt64(empty, NewInt64()) {
+		t.Fatal("invalid empty Equal")
+	}
+	if !DisjointInt64(empty, a) || !DisjointInt64(a, empty) || !DisjointInt64(empty, empty) {
+		t.Fatal("invalid empty Disjoint")
+	}
+
+	// Descending order and FuncSet.
+	sa, sb := OfStringDesc("a", "c"), OfStringDesc("a", "b", "c")
+	if !IsSubsetStringDesc(sa, sb) || IsSupersetStringDesc(sa, sb) || EqualStringDesc(sa, sb) {
+		t.Fatal("invalid StringSetDesc relations")
+	}
+	if DisjointStringDesc(sa, sb) || !DisjointStringDesc(sa, OfStringDesc("b", "d")) {
+		t.Fatal("invalid StringSetDesc Disjoint")
+	}
+	fa, fb := OfFloat64Desc(0.5, 2), OfFloat64Desc(4, 2, 1, 0.5)
+	if !IsSubsetFunc(fa, fb) || !IsSupersetFunc(fb, fa) || EqualFunc(fa, fb) || !EqualFunc(fa, OfFloat64Desc(2, 0.5)) {
+		t.Fatal("invalid FuncSet relations")
+	}
+	if DisjointFunc(fa, fb) || !DisjointFunc(fa, OfFloat64Desc(1, 4)) {
+		t.Fatal("invalid FuncSet Disjoint")
+	}
+
+	// Random sets against Contains.
+	for i := 0; i < 100; i++ {
+		x, y := New[int](), New[int]()
+		for j := fastrand.Uint32n(20); j > 0; j-- {
+			x.Add(int(fastrand.Uint32n(20)))
+		}
+		for j := fastrand.Uint32n(20); j > 0; j-- {
+			y.Add(int(fastrand.Uint32n(20)))
+		}
+		subset, superset, disjoint := true, true, true
+		for v := 0; v < 20; v++ {
+			inx, iny := x.Contains(v), y.Contains(v)
+			if inx && !iny {
+				subset = false
+			}
+			if iny && !inx {
+				superset = false
+			}
+			if inx && iny {
+				disjoint = false
+			}
+		}
+		if IsSubset(x, y) != subset || IsSuperset(x, y) != superset || Disjoint(x, y) != disjoint ||
+			Equal(x, y) != (subset && superset) {
+			t.Fatal("invalid relations", x, y)
+		}
+	}
+}
+
+type rangeLener[T any] interface {
+	Range(f func(value T) bool)
+	Len() int
+}
+
+func checkSet[T comparable, S rangeLener[T]](t *testing.T, s S, expected []T) {
+	t.Helper()
+	got := make([]T, 0, len(expected))
+	s.Range(func(value T) bool {
+		got = append(got, value)
+		return true
+	})
+	if !slicesEqual(got, expected) {
+		t.Fatalf("Expected: %v\n Got: %v\n", expected, got)
+	}
+	if s.Len() != len(expected) {
+		t.Fatalf("Expected length %d, got %d", len(expected), s.Len())
+	}
+}
+
+func TestDiff(t *testing.T) {
+	oldSet, newSet := NewString(), NewString()
+	for _, v := range []string{"a", "b", "d", "f"} {
+		oldSet.Add(v)
+	}
+	for _, v := range []string{"b", "c", "d", "g"} {
+		newSet.Add(v)
+	}
+	var added, removed []string
+	DiffString(oldSet, newSet, func(value string) bool {
+		added = append(added, value)
+		return true
+	}, func(value string) bool {
+		removed = append(removed, value)
+		return true
+	})
+	if !slicesEqual(added, []string{"c", "g"}) || !slicesEqual(removed, []string{"a", "f"}) {
+		t.Fatal(added, removed)
+	}
+
+	// Nil callbacks and stopping.
+	added = added[:0]
+	DiffString(oldSet, newSet, func(value string) bool {
+		added = append(added, value)
+		return false
+	}, nil)
+	if !slicesEqual(added, []string{"c"}) {
+		t.Fatal(added)
+	}
+
+	// Iterator.
+	type change struct {
+		value int
+		added bool
+	}
+	x, y := NewIntDesc(), NewIntDesc()
+	for _, v := range []int{1, 2, 3} {
+		x.Add(v)
+	}
+	for _, v := range []int{2, 3, 4} {
+		y.Add(v)
+	}
+	var changes []change
+	DiffSeqIntDesc(x, y)(func(value int, added bool) bool {
+		changes = append(changes, change{value, added})
+		return true
+	})
+	if !slicesEqual(changes, []change{{4, true}, {1, false}}) {
+		t.Fatal(changes)
+	}
+	changes = changes[:0]
+	DiffSeqIntDesc(x, x)(func(value int, added bool) bool {
+		changes = append(changes, change{value, added})
+		return true
+	})
+	if len(changes) != 0 {
+		t.Fatal(changes)
+	}
+}
@@ -0,0 +1,114 @@
+package skipset
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// binaryVersion is the version of the binary encoding of the integer skip sets.
+//
+// The encoding is the version byte, the number of values as a uvarint, the first value as a
+// varint (signed types) or uvarint (unsigned types), then the distance between each value and
+// the previous one as a uvarint.
+const binaryVersion = 1
+
+var (
+	errBinaryVersion = errors.New("skipset: unsupported binary encoding version")
+	errBinaryData    = errors.New("skipset: invalid binary data")
+)
+
+// marshalBinary encodes the values passed to f by rangeFn, which must be strictly ordered.
+func marshalBinary[T integer](rangeFn func(f func(value T) bool)) []byte {
+	var (
+		body  []byte
+		count uint64
+		prev  uint64
+		buf   [binary.MaxVarintLen64]byte
+	)
+	rangeFn(func(value T) bool {
+		var n int
+		k := integerKey(value)
+		switch {
+		case count == 0 && ^T(0) < 0:
+			n = binary.PutVarint(buf[:], int64(value))
+		case count == 0:
+			n = binary.PutUvarint(buf[:], uint64(value))
+		case k > prev:
+			n = binary.PutUvarint(buf[:], k-prev)
+		default:
+			n = binary.PutUvarint(buf[:], prev-k)
+		}
+		body = append(body, buf[:n]...)
+		prev = k
+		count++
+		return true
+	})
+	n := binary.PutUvarint(buf[:], count)
+	data := make([]byte, 0, 1+n+len(body))
+	data = append(data, binaryVersion)
+	data = append(data, buf[:n]...)
+	return append(data, body...)
+}
+
+// unmarshalBinary decodes the values encoded by marshalBinary, checking that they are strictly
+// ascending (or descending if desc), and passes them to f.
+func unmarshalBinary[T integer](data []byte, desc bool, f func(value T)) error {
+	if len(data) == 0 {
+		return errBinaryData
+	}
+	if data[0] != binaryVersion {
+		return errBinaryVersion
+	}
+	data = data[1:]
+	count, n := binary.Uvarint(data)
+	// Each value takes at least one byte.
+	if n <= 0 || count > uint64(len(data)-n) {
+		return errBinaryData
+	}
+	data = data[n:]
+	var prev uint64
+	for i := uint64(0); i < count; i++ {
+		var k uint64
+		if i == 0 {
+			if ^T(0) < 0 {
+				v, n := binary.Varint(data)
+				if n <= 0 || int64(T(v)) != v {
+					return errBinaryData
+				}
+				k, data = integerKey(T(v)), data[n:]
+			} else {
+				v, n := binary.Uvarint(data)
+				if n <= 0 || uint64(T(v)) != v {
+					return errBinaryData
+				}
+				k, data = v, data[n:]
+			}
+		} else {
+			delta, n := binary.Uvarint(data)
+			if n <= 0 || delta == 0 {
+				return errBinaryData
+			}
+			data = data[n:]
+			if desc {
+				k = prev - delta
+				if k > prev {
+					return errBinaryData
+				}
+			} else {
+				k = prev + delta
+				if k < prev {
+					return errBinaryData
+				}
+			}
+			if integerKey(integerValue[T](k)) != k {
+				return errBinaryData // out of the range of T
+			}
+		}
+		f(integerValue[T](k))
+		prev = k
+	}
+	if len(data) != 0 {
+		return errBinaryData
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package skipset
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestBinary(t *testing.T) {
+	s := NewInt64()
+	values := []int64{math.MinInt64, -300, -1, 0, 1, 127, 128, 1 << 40, math.MaxInt64}
+	for _, v := range values {
+		s.Add(v)
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2 := NewInt64()
+	s2.Add(42)
+	if err := s2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int64](t, s2, values)
+	s2.Add(42) // the decoded skip set is usable
+	if !s2.Contains(42) || s2.Len() != len(values)+1 {
+		t.Fatal("invalid skip set")
+	}
+
+	d := NewUint32Desc()
+	for _, v := range []uint32{0, 1, math.MaxUint32, 1000} {
+		d.Add(v)
+	}
+	data, _ = d.MarshalBinary()
+	var d2 Uint32SetDesc
+	if err := d2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[uint32](t, &d2, []uint32{math.MaxUint32, 1000, 1, 0})
+
+	// The ordering and the range of the values are checked.
+	if err := NewUint32().UnmarshalBinary(data); err != errBinaryData {
+		t.Fatal("descending values should be rejected", err)
+	}
+	data, _ = NewUint64Desc().MarshalBinary()
+	if !bytes.Equal(data, []byte{binaryVersion, 0}) {
+		t.Fatal("invalid empty encoding", data)
+	}
+	for _, data := range [][]byte{
+		nil,
+		{binaryVersion},
+		{binaryVersion, 2, 1},    // truncated
+		{binaryVersion, 1, 1, 1}, // trailing data
+		{binaryVersion, 2, 1, 0}, // duplicate
+		{binaryVersion, 1, 0xff, 0xff, 0xff, 0xff, 0x1f},    // out of range
+		{binaryVersion, 2, 0xfe, 0xff, 0xff, 0xff, 0x0f, 2}, // overflow
+	} {
+		if err := NewUint32().UnmarshalBinary(data); err != errBinaryData {
+			t.Fatal("invalid data should be rejected", data, err)
+		}
+	}
+	if err := NewInt().UnmarshalBinary([]byte{binaryVersion + 1, 0}); err != errBinaryVersion {
+		t.Fatal("invalid version should be rejected", err)
+	}
+
+	// Dense values take about one byte each.
+	u := NewUint64()
+	for i := uint64(0); i < 10000; i++ {
+		u.Add(1<<40 + 3*i)
+	}
+	data, _ = u.MarshalBinary()
+	js, _ := json.Marshal(u)
+	if len(data) > 10010 || len(data)*10 > len(js) {
+		t.Fatal("invalid encoding size", len(data), len(js))
+	}
+}
+
+func FuzzInt64SetUnmarshalBinary(f *testing.F) {
+	s := NewInt64()
+	for _, v := range []int64{math.MinInt64, -1, 0, 5, math.MaxInt64} {
+		s.Add(v)
+		data, _ := s.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzBinary[int64](t, NewInt64(), data)
+	})
+}
+
+func FuzzUint32SetDescUnmarshalBinary(f *testing.F) {
+	s := NewUint32Desc()
+	for _, v := range []uint32{0, 7, math.MaxUint32} {
+		s.Add(v)
+		data, _ := s.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzBinary[uint32](t, NewUint32Desc(), data)
+	})
+}
+
+type binarySet[T any] interface {
+	rangeLener[T]
+	Contains(value T) bool
+	MarshalBinary() ([]byte, error)
+	UnmarshalBinary(data []byte) error
+}
+
+// fuzzBinary checks that s either rejects data, or decodes it into a valid skip set which is
+// encoded back to equivalent data.
+func fuzzBinary[T comparable](t *testing.T, s binarySet[T], data []byte) {
+	if err := s.UnmarshalBinary(data); err != nil {
+		if s.Len() != 0 {
+			t.Fatal("the skip set should be unchanged")
+		}
+		return
+	}
+	var values []T
+	s.Range(func(value T) bool {
+		if !s.Contains(value) {
+			t.Fatal("invalid skip set", value)
+		}
+		values = append(values, value)
+		return true
+	})
+	if len(values) != s.Len() {
+		t.Fatal("invalid length", len(values), s.Len())
+	}
+	encoded, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UnmarshalBinary(encoded); err != nil {
+		t.Fatal(err)
+	}
+	checkSet(t, s, values)
+}
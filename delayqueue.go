@@ -0,0 +1,129 @@
+package skipset
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// DelayQueue is a concurrent queue of values which can only be taken once they are due.
+// The values are kept in a skip set ordered by due time.
+type DelayQueue[T any] struct {
+	clock Clock
+	seq   uint64 // breaks the ties between equal due times
+	set   *FuncSet[*DelayItem[T]]
+}
+
+// DelayItem is a value scheduled in a DelayQueue.
+// The due time is the key of the item in the queue, so it can't be changed once added.
+type DelayItem[T any] struct {
+	value T
+	due   time.Time
+	seq   uint64
+}
+
+// Value returns the value of the item.
+func (i *DelayItem[T]) Value() T {
+	return i.value
+}
+
+// Due returns the time at which the item is due.
+func (i *DelayItem[T]) Due() time.Time {
+	return i.due
+}
+
+// NewDelayQueue returns an empty delay queue using the given clock, or the system clock if it is nil.
+func NewDelayQueue[T any](clock Clock) *DelayQueue[T] {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &DelayQueue[T]{
+		clock: clock,
+		set: NewFunc(func(a, b *DelayItem[T]) bool {
+			return a.due.Before(b.due) || (a.due.Equal(b.due) && a.seq < b.seq)
+		}),
+	}
+}
+
+// Add schedules the value to be due at the given time, and wakes up the consumers blocked
+// in Take so that they can wait for it if it is due earlier. The returned item can be
+// passed to Cancel.
+func (q *DelayQueue[T]) Add(value T, due time.Time) *DelayItem[T] {
+	item := &DelayItem[T]{
+		value: value,
+		due:   due,
+		seq:   atomic.AddUint64(&q.seq, 1),
+	}
+	q.set.Add(item)
+	return item
+}
+
+// Cancel removes the item from the queue, it returns false if the item has already been
+// taken or canceled.
+func (q *DelayQueue[T]) Cancel(item *DelayItem[T]) bool {
+	return q.set.Remove(item)
+}
+
+// Poll removes and returns the earliest value which is due, it returns false if there is none.
+func (q *DelayQueue[T]) Poll() (T, bool) {
+	for {
+		x := q.set.header.atomicLoadNextValid()
+		if x == nil || x.value.due.After(q.clock.Now()) {
+			var zero T
+			return zero, false
+		}
+		if q.set.Remove(x.value) {
+			return x.value.value, true
+		}
+	}
+}
+
+// Take removes and returns the earliest value once it is due, blocking until then.
+// It sleeps until the earliest due time and is woken up early if an earlier value is added.
+// It returns ctx.Err() if ctx is done before a value is due.
+func (q *DelayQueue[T]) Take(ctx context.Context) (T, error) {
+	n := loadOrStoreNotifier(&q.set.notify)
+	for {
+		ch := n.wait()
+		x := q.set.header.atomicLoadNextValid()
+		if x == nil {
+			select {
+			case <-ch:
+				continue
+			case <-ctx.Done():
+				var zero T
+				return zero, ctx.Err()
+			}
+		}
+		d := x.value.due.Sub(q.clock.Now())
+		if d <= 0 {
+			if q.set.Remove(x.value) {
+				return x.value.value, nil
+			}
+			continue
+		}
+		timer := q.clock.NewTimer(d)
+		for waiting := true; waiting; {
+			select {
+			case <-timer.C():
+				waiting = false
+			case <-ch:
+				// Keep the timer while x is still the earliest value, the added value is due later.
+				ch = n.wait()
+				if q.set.header.atomicLoadNextValid() != x {
+					timer.Stop()
+					waiting = false
+				}
+			case <-ctx.Done():
+				timer.Stop()
+				var zero T
+				return zero, ctx.Err()
+			}
+		}
+	}
+}
+
+// Len returns the number of values in the queue, due or not.
+func (q *DelayQueue[T]) Len() int {
+	return q.set.Len()
+}
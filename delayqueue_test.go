@@ -0,0 +1,228 @@
+package skipset
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock which only moves forward when advanced.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	timers  []*fakeTimer
+	created int // the number of timers created
+}
+
+type fakeTimer struct {
+	c       chan time.Time
+	due     time.Time
+	stopped bool
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{c: make(chan time.Time, 1), due: c.now.Add(d)}
+	c.timers = append(c.timers, t)
+	c.created++
+	return &fakeTimerHandle{c, t}
+}
+
+// armed reports whether the only running timer fires at due.
+func (c *fakeClock) armed(due time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers) == 1 && c.timers[0].due.Equal(due)
+}
+
+// waitArmed blocks until the only running timer fires after d.
+func (c *fakeClock) waitArmed(d time.Duration) {
+	for due := c.Now().Add(d); !c.armed(due); {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	timers := c.timers[:0]
+	for _, t := range c.timers {
+		if t.due.After(c.now) {
+			timers = append(timers, t)
+			continue
+		}
+		t.stopped = true
+		t.c <- c.now
+	}
+	c.timers = timers
+}
+
+type fakeTimerHandle struct {
+	clock *fakeClock
+	t     *fakeTimer
+}
+
+func (h *fakeTimerHandle) C() <-chan time.Time {
+	return h.t.c
+}
+
+func (h *fakeTimerHandle) Stop() bool {
+	h.clock.mu.Lock()
+	defer h.clock.mu.Unlock()
+	if h.t.stopped {
+		return false
+	}
+	h.t.stopped = true
+	for i, t := range h.clock.timers {
+		if t == h.t {
+			h.clock.timers = append(h.clock.timers[:i], h.clock.timers[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func TestDelayQueue(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	q := NewDelayQueue[string](clock)
+	if _, ok := q.Poll(); ok {
+		t.Fatal("invalid poll")
+	}
+	q.Add("c", clock.Now().Add(3*time.Second))
+	q.Add("a", clock.Now().Add(time.Second))
+	b := q.Add("b", clock.Now().Add(2*time.Second))
+	q.Add("a2", clock.Now().Add(time.Second))
+	if q.Len() != 4 {
+		t.Fatal("invalid length")
+	}
+	if b.Value() != "b" || !b.Due().Equal(clock.Now().Add(2*time.Second)) {
+		t.Fatal("invalid item", b.Value(), b.Due())
+	}
+	if _, ok := q.Poll(); ok {
+		t.Fatal("poll before due")
+	}
+	clock.Advance(time.Second)
+	for _, expected := range []string{"a", "a2"} {
+		if v, ok := q.Poll(); !ok || v != expected {
+			t.Fatal("invalid poll", v, expected)
+		}
+	}
+	if !q.Cancel(b) || q.Cancel(b) {
+		t.Fatal("invalid cancel")
+	}
+
+	// Take waits for the earliest due time.
+	result := make(chan string)
+	take := func() {
+		v, err := q.Take(context.Background())
+		if err != nil {
+			panic(err)
+		}
+		result <- v
+	}
+	go take()
+	clock.waitArmed(2 * time.Second)
+	clock.Advance(time.Second)
+	select {
+	case v := <-result:
+		t.Fatal("taken before due", v)
+	case <-time.After(10 * time.Millisecond):
+	}
+	clock.Advance(time.Second)
+	if v := <-result; v != "c" {
+		t.Fatal("invalid take", v)
+	}
+
+	// Adding an earlier value re-arms the consumer.
+	go take()
+	q.Add("late", clock.Now().Add(time.Hour))
+	clock.waitArmed(time.Hour)
+	q.Add("early", clock.Now().Add(time.Minute))
+	clock.waitArmed(time.Minute)
+	clock.Advance(time.Minute)
+	if v := <-result; v != "early" {
+		t.Fatal("invalid take", v)
+	}
+
+	// Adding a later value doesn't re-arm the consumer.
+	go take()
+	clock.waitArmed(time.Hour - time.Minute)
+	clock.mu.Lock()
+	created := clock.created
+	clock.mu.Unlock()
+	q.Add("later", clock.Now().Add(2*time.Hour))
+	time.Sleep(10 * time.Millisecond)
+	clock.mu.Lock()
+	if clock.created != created {
+		t.Fatal("timer re-armed for a later value")
+	}
+	clock.mu.Unlock()
+	clock.waitArmed(time.Hour - time.Minute)
+	clock.Advance(time.Hour - time.Minute)
+	if v := <-result; v != "late" {
+		t.Fatal("invalid take", v)
+	}
+
+	// Context done.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.Take(ctx); err != context.Canceled {
+		t.Fatal("invalid error", err)
+	}
+}
+
+func TestDelayQueueConcurrent(t *testing.T) {
+	const (
+		consumers = 8
+		n         = 1000
+	)
+	q := NewDelayQueue[int](nil)
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		q.Add(i, now.Add(time.Duration(i%10)*time.Millisecond))
+	}
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		delivered = make(map[int]bool)
+	)
+	record := func(v int) {
+		mu.Lock()
+		defer mu.Unlock()
+		if delivered[v] {
+			panic("double delivery")
+		}
+		delivered[v] = true
+	}
+	for i := 0; i < consumers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for q.Len() != 0 {
+				if v, ok := q.Poll(); ok {
+					record(v)
+					continue
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+				v, err := q.Take(ctx)
+				cancel()
+				if err == nil {
+					record(v)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if len(delivered) != n {
+		t.Fatal("invalid count", len(delivered))
+	}
+}
@@ -0,0 +1,186 @@
+package skipset
+
+import (
+	"math/bits"
+	"runtime"
+	"sync/atomic"
+)
+
+const (
+	denseChunkShift = 10 // a chunk holds 1024 consecutive integers
+	denseChunkWords = 1 << denseChunkShift / 64
+)
+
+// DenseSet represents a set of integers based on skip list, optimized for dense data. Each node
+// holds a bitmap chunk of 1024 consecutive integers, so long runs cost about one bit per value
+// instead of a node per value.
+//
+// DenseSet has the same API and concurrency guarantees as the integer skip sets: Contains,
+// Range and RangeFrom are wait-free, and Add and Remove only lock the skip list to insert or
+// unlink a chunk.
+type DenseSet[T integer] struct {
+	length int64
+	list   *FuncSet[*denseChunk]
+}
+
+// denseChunk holds the integers whose key is in [key<<denseChunkShift, (key+1)<<denseChunkShift).
+type denseChunk struct {
+	key   uint64
+	count int64 // the number of set bits plus the pending Adds, or -1 once the chunk is dead
+	bits  [denseChunkWords]uint64
+}
+
+// acquire reserves a bit of c for an Add, it returns false if c is dead.
+func (c *denseChunk) acquire() bool {
+	for {
+		n := atomic.LoadInt64(&c.count)
+		if n < 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&c.count, n, n+1) {
+			return true
+		}
+	}
+}
+
+// release releases a bit of c, it returns true if c is empty and this call marked it as dead.
+func (c *denseChunk) release() bool {
+	return atomic.AddInt64(&c.count, -1) == 0 && atomic.CompareAndSwapInt64(&c.count, 0, -1)
+}
+
+func (c *denseChunk) contains(k uint64) bool {
+	return atomic.LoadUint64(&c.bits[k%(1<<denseChunkShift)/64])&(1<<(k%64)) != 0
+}
+
+// update sets or clears the bit of k, it returns false if the bit is unchanged.
+func (c *denseChunk) update(k uint64, set bool) bool {
+	w, mask := &c.bits[k%(1<<denseChunkShift)/64], uint64(1)<<(k%64)
+	for {
+		old := atomic.LoadUint64(w)
+		if (old&mask != 0) == set {
+			return false
+		}
+		if atomic.CompareAndSwapUint64(w, old, old^mask) {
+			return true
+		}
+	}
+}
+
+// NewDense returns an empty dense set in ascending order.
+func NewDense[T integer]() *DenseSet[T] {
+	return &DenseSet[T]{
+		list: NewFunc(func(a, b *denseChunk) bool {
+			return a.key < b.key
+		}),
+	}
+}
+
+// ceil returns the first valid node whose chunk key is >= key, or nil if there is none.
+func (s *DenseSet[T]) ceil(key uint64) *funcnode[*denseChunk] {
+	x := s.list.header
+	for i := int(atomic.LoadUint64(&s.list.highestLevel)) - 1; i >= 0; i-- {
+		for next := x.atomicLoadNext(i); next != nil && next.value.key < key; next = x.atomicLoadNext(i) {
+			x = next
+		}
+	}
+	return x.atomicLoadNextValid()
+}
+
+// find returns the chunk of k, or nil if there is none.
+func (s *DenseSet[T]) find(k uint64) *denseChunk {
+	if x := s.ceil(k >> denseChunkShift); x != nil && x.value.key == k>>denseChunkShift {
+		return x.value
+	}
+	return nil
+}
+
+// Add adds the value into the set, returns true if this process inserts the value into the set,
+// returns false if this process can't insert this value, because another process has inserted the same value.
+func (s *DenseSet[T]) Add(value T) bool {
+	k := integerKey(value)
+	for {
+		c := s.find(k)
+		if c == nil {
+			c = &denseChunk{key: k >> denseChunkShift, count: 1}
+			c.update(k, true)
+			if s.list.Add(c) {
+				atomic.AddInt64(&s.length, 1)
+				return true
+			}
+			continue // another chunk has been inserted concurrently
+		}
+		if c.contains(k) {
+			return false
+		}
+		if !c.acquire() {
+			// The chunk is being unlinked, wait for it.
+			runtime.Gosched()
+			continue
+		}
+		if c.update(k, true) {
+			atomic.AddInt64(&s.length, 1)
+			return true
+		}
+		if c.release() {
+			s.list.Remove(c)
+		}
+		return false
+	}
+}
+
+// Contains checks if the value is in the set.
+func (s *DenseSet[T]) Contains(value T) bool {
+	k := integerKey(value)
+	c := s.find(k)
+	return c != nil && c.contains(k)
+}
+
+// Remove removes a value from the set.
+func (s *DenseSet[T]) Remove(value T) bool {
+	k := integerKey(value)
+	c := s.find(k)
+	if c == nil || !c.update(k, false) {
+		return false
+	}
+	atomic.AddInt64(&s.length, -1)
+	if c.release() {
+		s.list.Remove(c)
+	}
+	return true
+}
+
+// Range calls f sequentially for each value present in the set, in ascending order.
+// If f returns false, range stops the iteration.
+func (s *DenseSet[T]) Range(f func(value T) bool) {
+	s.rangeFrom(s.list.header.atomicLoadNextValid(), 0, f)
+}
+
+// RangeFrom calls f sequentially for all values with `value >= start` in the set, in ascending order.
+// If f returns false, range stops the iteration.
+func (s *DenseSet[T]) RangeFrom(start T, f func(value T) bool) {
+	k := integerKey(start)
+	s.rangeFrom(s.ceil(k>>denseChunkShift), k, f)
+}
+
+// rangeFrom calls f for the values with a key >= start, from the chunk of x.
+func (s *DenseSet[T]) rangeFrom(x *funcnode[*denseChunk], start uint64, f func(value T) bool) {
+	for ; x != nil; x = x.atomicLoadNextValid() {
+		c := x.value
+		base := c.key << denseChunkShift
+		for i := range c.bits {
+			w := atomic.LoadUint64(&c.bits[i])
+			for w != 0 {
+				k := base + uint64(i*64+bits.TrailingZeros64(w))
+				w &= w - 1
+				if k >= start && !f(integerValue[T](k)) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Len returns the length of this set.
+func (s *DenseSet[T]) Len() int {
+	return int(atomic.LoadInt64(&s.length))
+}
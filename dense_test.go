@@ -0,0 +1,118 @@
+package skipset
+
+import (
+	"math"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestDenseSet(t *testing.T) {
+	testIntSet(t, func() anyskipset[int] {
+		return NewDense[int]()
+	})
+
+	// Values across chunk and sign boundaries.
+	s := NewDense[int64]()
+	values := []int64{math.MinInt64, -1025, -1024, -1, 0, 1, 1023, 1024, math.MaxInt64}
+	for i := len(values) - 1; i >= 0; i-- {
+		if !s.Add(values[i]) || s.Add(values[i]) {
+			t.Fatal("invalid add", values[i])
+		}
+	}
+	checkSet[int64](t, s, values)
+	var got []int64
+	s.RangeFrom(-1024, func(v int64) bool {
+		got = append(got, v)
+		return v < 1
+	})
+	if !slicesEqual(got, []int64{-1024, -1, 0, 1}) {
+		t.Fatal("invalid RangeFrom", got)
+	}
+	for _, v := range values {
+		if !s.Remove(v) || s.Remove(v) || s.Contains(v) {
+			t.Fatal("invalid remove", v)
+		}
+	}
+	if s.Len() != 0 || s.list.Len() != 0 {
+		t.Fatal("empty chunks should be removed", s.Len(), s.list.Len())
+	}
+
+	u := NewDense[uint64]()
+	u.Add(math.MaxUint64)
+	u.Add(0)
+	checkSet[uint64](t, u, []uint64{0, math.MaxUint64})
+}
+
+func TestDenseSetRandom(t *testing.T) {
+	s := NewDense[int16]()
+	var model [1 << 16]bool
+	for i := 0; i < 100000; i++ {
+		v := int16(fastrand.Uint32n(1 << 16))
+		if fastrand.Uint32n(3) == 0 {
+			if s.Remove(v) != model[uint16(v)] {
+				t.Fatal("invalid remove", v)
+			}
+			model[uint16(v)] = false
+		} else {
+			if s.Add(v) == model[uint16(v)] {
+				t.Fatal("invalid add", v)
+			}
+			model[uint16(v)] = true
+		}
+	}
+	var expected []int16
+	for v := math.MinInt16; v <= math.MaxInt16; v++ {
+		if model[uint16(v)] {
+			expected = append(expected, int16(v))
+		}
+	}
+	checkSet[int16](t, s, expected)
+}
+
+func TestDenseSetConcurrent(t *testing.T) {
+	const n = 4096
+	s := NewDense[uint32]()
+	// The odd values are stable, the even ones are added and removed concurrently.
+	for v := uint32(1); v < n; v += 2 {
+		s.Add(v)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10000; j++ {
+				v := fastrand.Uint32n(n/2) * 2
+				if fastrand.Uint32n(2) == 0 {
+					s.Add(v)
+				} else {
+					s.Remove(v)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		for v := uint32(1); v < n; v += 2 {
+			if !s.Contains(v) {
+				t.Fatal("lost value", v)
+			}
+		}
+	}
+	wg.Wait()
+	var count int
+	s.Range(func(v uint32) bool {
+		count++
+		return true
+	})
+	if count != s.Len() {
+		t.Fatal("invalid length", count, s.Len())
+	}
+	for v := uint32(0); v < n; v++ {
+		s.Remove(v)
+	}
+	if s.Len() != 0 || s.list.Len() != 0 {
+		t.Fatal("invalid length", s.Len(), s.list.Len())
+	}
+}
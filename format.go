@@ -0,0 +1,46 @@
+package skipset
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// formatLimit is the default maximum number of values shown by Format.
+const formatLimit = 16
+
+// formatSet formats the values passed to emit by walk as {v1, v2, ...}, emit takes the count of
+// each value for the multisets, or -1. walk may be nil if there is no value.
+func formatSet(f fmt.State, verb rune, length int, walk func(emit func(value any, count int) bool)) {
+	limit := formatLimit
+	if p, ok := f.Precision(); ok {
+		limit = p
+	}
+	elem := "%" + string(verb)
+	if f.Flag('#') {
+		elem = "%#" + string(verb)
+	}
+	if f.Flag('+') {
+		io.WriteString(f, "len="+strconv.Itoa(length)+" ")
+	}
+	io.WriteString(f, "{")
+	if walk != nil {
+		n := 0
+		walk(func(value any, count int) bool {
+			if n != 0 {
+				io.WriteString(f, ", ")
+			}
+			if n == limit {
+				io.WriteString(f, "...")
+				return false
+			}
+			fmt.Fprintf(f, elem, value)
+			if count >= 0 {
+				io.WriteString(f, ":"+strconv.Itoa(count))
+			}
+			n++
+			return true
+		})
+	}
+	io.WriteString(f, "}")
+}
@@ -0,0 +1,55 @@
+package skipset
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	s := NewIntDesc()
+	for i := 0; i < 20; i++ {
+		s.Add(i)
+	}
+	m := NewStringMulti()
+	for _, v := range []string{"b", "a", "b"} {
+		m.Add(v)
+	}
+	f := NewFunc(func(a, b string) bool { return a < b })
+	f.Add("x")
+	var z OrderedSet[float64]
+	for _, c := range []struct {
+		format string
+		arg    any
+		want   string
+	}{
+		{"%v", s, "{19, 18, 17, 16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, ...}"},
+		{"%.3v", s, "{19, 18, 17, ...}"},
+		{"%+.2d", s, "len=20 {19, 18, ...}"},
+		{"%.0v", s, "{...}"},
+		{"%x", s, "{13, 12, 11, 10, f, e, d, c, b, a, 9, 8, 7, 6, 5, 4, ...}"},
+		{"%v", m, "{a:1, b:2}"},
+		{"%+q", m, `len=2 {"a":1, "b":2}`},
+		{"%s", f, "{x}"},
+		{"%#v", f, `{"x"}`},
+		{"%+v", &z, "len=0 {}"},
+		{"%v", New[int](), "{}"},
+	} {
+		if got := fmt.Sprintf(c.format, c.arg); got != c.want {
+			t.Fatalf("%s: got %s, expected %s", c.format, got, c.want)
+		}
+	}
+	if got := New[uint64]().String(); got != "{}" {
+		t.Fatal("invalid String", got)
+	}
+	// The flag types keep the syntax of flag.Value in String, and the braces in Format.
+	u := OfUint64(443, 80)
+	if got := u.String(); got != "80,443" {
+		t.Fatal("invalid String", got)
+	}
+	if got := fmt.Sprint(u); got != "{80, 443}" {
+		t.Fatal("invalid Sprint", got)
+	}
+	if got := fmt.Sprint(struct{ S *MultiSet[int] }{NewMulti[int]()}); got != "{{}}" {
+		t.Fatal("invalid String", got)
+	}
+}
@@ -0,0 +1,75 @@
+package skipset
+
+import (
+	"testing"
+)
+
+func TestFromSorted(t *testing.T) {
+	s, err := NewIntFromSorted([]int{-3, 1, 1, 2, 5, 5, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int](t, s, []int{-3, 1, 2, 5})
+	if !s.Add(3) || s.Add(1) || !s.Remove(5) {
+		t.Fatal("the skip set should be usable")
+	}
+	checkSet[int](t, s, []int{-3, 1, 2, 3})
+
+	d, err := NewDescFromSorted([]string{"c", "b", "b", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSet[string](t, d, []string{"c", "b", "a"})
+	f, err := NewFuncFromSorted(func(a, b float64) bool { return a < b }, []float64{0.5, 1.5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkSet[float64](t, f, []float64{0.5, 1.5})
+	m, err := NewStringMultiDescFromSorted([]string{"b", "b", "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkMultiSet(t, m.Range, []string{"b", "a"}, []int{2, 1})
+
+	if _, err := NewUint64FromSorted([]uint64{1, 3, 2}); err != ErrNotSorted {
+		t.Fatal("invalid error", err)
+	}
+	if _, err := NewInt64DescFromSorted([]int64{1, 2}); err != ErrNotSorted {
+		t.Fatal("invalid error", err)
+	}
+	if e, err := NewFromSorted[int](nil); err != nil || e.Len() != 0 {
+		t.Fatal("invalid empty skip set", err)
+	}
+}
+
+func TestFromSortedLevels(t *testing.T) {
+	const n = 1 << 16
+	values := make([]uint32, n)
+	for i := range values {
+		values[i] = uint32(i)
+	}
+	s, err := NewUint32FromSorted(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every level must be sorted, and hold about p^level of the values like with Add.
+	expected := float64(n)
+	for l := 0; l < 4; l++ {
+		count := 0
+		for x := s.header.loadNext(l); x != nil; x = x.loadNext(l) {
+			if next := x.loadNext(l); next != nil && x.value >= next.value {
+				t.Fatal("invalid level", l)
+			}
+			count++
+		}
+		if float64(count) < 0.8*expected || float64(count) > 1.2*expected {
+			t.Fatalf("level %d: got %d nodes, expected about %v", l, count, expected)
+		}
+		expected *= p
+	}
+	for _, v := range values {
+		if !s.Contains(v) {
+			t.Fatal("invalid tower", v)
+		}
+	}
+}
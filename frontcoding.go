@@ -0,0 +1,86 @@
+package skipset
+
+import "encoding/binary"
+
+const (
+	// frontCodingVersion is the version of the binary encoding of the string skip sets.
+	//
+	// The encoding is the version byte, the number of values as a uvarint, then the values in
+	// blocks of frontCodingRestart. The first value of a block, called a restart point, is its
+	// length as a uvarint followed by its bytes. Each other value is the length of the prefix it
+	// shares with the previous value and the length of the rest as uvarints, followed by the rest.
+	frontCodingVersion = 1
+	frontCodingRestart = 16
+)
+
+// marshalFrontCoded encodes the values passed to f by rangeFn.
+func marshalFrontCoded(rangeFn func(f func(value string) bool)) []byte {
+	var (
+		body  []byte
+		count uint64
+		prev  string
+		buf   [binary.MaxVarintLen64]byte
+	)
+	rangeFn(func(value string) bool {
+		shared := 0
+		if count%frontCodingRestart != 0 {
+			for shared < len(prev) && shared < len(value) && prev[shared] == value[shared] {
+				shared++
+			}
+			body = append(body, buf[:binary.PutUvarint(buf[:], uint64(shared))]...)
+		}
+		body = append(body, buf[:binary.PutUvarint(buf[:], uint64(len(value)-shared))]...)
+		body = append(body, value[shared:]...)
+		prev = value
+		count++
+		return true
+	})
+	n := binary.PutUvarint(buf[:], count)
+	data := make([]byte, 0, 1+n+len(body))
+	data = append(data, frontCodingVersion)
+	data = append(data, buf[:n]...)
+	return append(data, body...)
+}
+
+// unmarshalFrontCoded decodes the values encoded by marshalFrontCoded, checking that each value
+// is less than the next one, and passes them to f.
+func unmarshalFrontCoded(data []byte, less func(a, b string) bool, f func(value string)) error {
+	if len(data) == 0 {
+		return errBinaryData
+	}
+	if data[0] != frontCodingVersion {
+		return errBinaryVersion
+	}
+	data = data[1:]
+	count, n := binary.Uvarint(data)
+	// Each value takes at least one byte.
+	if n <= 0 || count > uint64(len(data)-n) {
+		return errBinaryData
+	}
+	data = data[n:]
+	var prev string
+	for i := uint64(0); i < count; i++ {
+		var shared uint64
+		if i%frontCodingRestart != 0 {
+			if shared, n = binary.Uvarint(data); n <= 0 || shared > uint64(len(prev)) {
+				return errBinaryData
+			}
+			data = data[n:]
+		}
+		size, n := binary.Uvarint(data)
+		if n <= 0 || size > uint64(len(data)-n) {
+			return errBinaryData
+		}
+		value := prev[:shared] + string(data[n:n+int(size)])
+		data = data[n+int(size):]
+		if i != 0 && !less(prev, value) {
+			return errBinaryData
+		}
+		f(value)
+		prev = value
+	}
+	if len(data) != 0 {
+		return errBinaryData
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package skipset
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestFrontCoding(t *testing.T) {
+	s := NewString()
+	var values []string
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("https://example.com/items/%05d", i))
+	}
+	values = append([]string{"", "a", "ab", "b"}, values...)
+	for _, v := range values {
+		s.Add(v)
+	}
+	data, err := s.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s2 StringSet
+	if err := s2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[string](t, &s2, values)
+	if size := len(data); size > 1000 {
+		t.Fatal("shared prefixes should be encoded once per block", size)
+	}
+
+	d := NewStringDesc()
+	for _, v := range values {
+		d.Add(v)
+	}
+	data, _ = d.MarshalBinary()
+	d2 := NewStringDesc()
+	if err := d2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !EqualString(s, &s2) || d2.Len() != len(values) {
+		t.Fatal("invalid skip set")
+	}
+	if err := NewString().UnmarshalBinary(data); err != errBinaryData {
+		t.Fatal("descending values should be rejected", err)
+	}
+
+	data, _ = NewStringDesc().MarshalBinary()
+	if !bytes.Equal(data, []byte{frontCodingVersion, 0}) {
+		t.Fatal("invalid empty encoding", data)
+	}
+	for _, data := range [][]byte{
+		nil,
+		{frontCodingVersion, 1},               // truncated
+		{frontCodingVersion, 1, 2, 'a'},       // truncated
+		{frontCodingVersion, 1, 0, 0},         // trailing data
+		{frontCodingVersion, 2, 1, 'a', 2, 0}, // shared prefix too long
+		{frontCodingVersion, 2, 1, 'a', 1, 0}, // duplicate
+	} {
+		if err := NewString().UnmarshalBinary(data); err != errBinaryData {
+			t.Fatal("invalid data should be rejected", data, err)
+		}
+	}
+	if err := NewString().UnmarshalBinary([]byte{frontCodingVersion + 1, 0}); err != errBinaryVersion {
+		t.Fatal("invalid version should be rejected", err)
+	}
+}
+
+func FuzzStringSetUnmarshalBinary(f *testing.F) {
+	s := NewString()
+	for _, v := range []string{"", "abc", "abd", "b"} {
+		s.Add(v)
+		data, _ := s.MarshalBinary()
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		fuzzBinary[string](t, NewString(), data)
+	})
+}
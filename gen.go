@@ -34,6 +34,19 @@ type Variant struct {
 	StructSuffix    string
 	ExtraFileds     string
 
+	// Multiset reports whether the nodes carry a count of occurrences.
+	Multiset bool
+
+	// Integer reports whether Type is a builtin integer type.
+	Integer bool
+
+	// HasLess reports whether the set orders values with its own less function.
+	HasLess bool
+
+	// NewSuffix is the suffix of the constructor of this variant, e.g. "IntDesc" for NewIntDesc.
+	// Package-level functions of this variant use the same suffix.
+	NewSuffix string
+
 	// Basic type. T or "".
 	Type string
 
@@ -59,13 +72,14 @@ func main() {
 		Package:         "skipset",
 		Name:            "ordered",
 		Path:            "gen_ordered.go",
-		Imports:         "\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
+		Imports:         "\"context\"\n\"fmt\"\n\"io\"\n\"sort\"\n\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
 		Type:            "T",
 		TypeArgument:    "[T]",
 		TypeParam:       "[T ordered]",
 		StructPrefix:    "Ordered",
 		StructPrefixLow: "ordered",
 		StructSuffix:    "",
+		NewSuffix:       "",
 		Funcs: template.FuncMap{
 			"Less": func(i, j string) string {
 				return fmt.Sprintf("(%s < %s)", i, j)
@@ -78,6 +92,7 @@ func main() {
 	generate(base)
 	base.Name += "Desc"
 	base.StructSuffix += "Desc"
+	base.NewSuffix += "Desc"
 	base.Path = "gen_ordereddesc.go"
 	base.Funcs = template.FuncMap{
 		"Less": func(i, j string) string {
@@ -94,14 +109,16 @@ func main() {
 		Package:         "skipset",
 		Name:            "func",
 		Path:            "gen_func.go",
-		Imports:         "\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
+		Imports:         "\"context\"\n\"fmt\"\n\"io\"\n\"sort\"\n\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
 		Type:            "T",
 		TypeArgument:    "[T]",
 		TypeParam:       "[T any]",
 		ExtraFileds:     "\nless func(a,b T)bool\n",
+		HasLess:         true,
 		StructPrefix:    "Func",
 		StructPrefixLow: "func",
 		StructSuffix:    "",
+		NewSuffix:       "Func",
 		Funcs: template.FuncMap{
 			"Less": func(i, j string) string {
 				return fmt.Sprintf("s.less(%s,%s)", i, j)
@@ -120,13 +137,14 @@ func main() {
 			Package:         "skipset",
 			Name:            "{{TypeLow}}",
 			Path:            "gen_{{TypeLow}}.go",
-			Imports:         "\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
+			Imports:         "\"context\"\n\"database/sql/driver\"\n\"fmt\"\n\"io\"\n\"sort\"\n\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
 			Type:            "{{TypeLow}}",
 			TypeArgument:    "",
 			TypeParam:       "",
 			StructPrefix:    "{{Type}}",
 			StructPrefixLow: "{{TypeLow}}",
 			StructSuffix:    "",
+			NewSuffix:       "{{Type}}",
 			Funcs: template.FuncMap{
 				"Less": func(i, j string) string {
 					return fmt.Sprintf("(%s < %s)", i, j)
@@ -140,13 +158,14 @@ func main() {
 			Package:         "skipset",
 			Name:            "{{TypeLow}}Desc",
 			Path:            "gen_{{TypeLow}}desc.go",
-			Imports:         "\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
+			Imports:         "\"context\"\n\"database/sql/driver\"\n\"fmt\"\n\"io\"\n\"sort\"\n\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
 			Type:            "{{TypeLow}}",
 			TypeArgument:    "",
 			TypeParam:       "",
 			StructPrefix:    "{{Type}}",
 			StructPrefixLow: "{{TypeLow}}",
 			StructSuffix:    "Desc",
+			NewSuffix:       "{{Type}}Desc",
 			Funcs: template.FuncMap{
 				"Less": func(i, j string) string {
 					return fmt.Sprintf("(%s > %s)", i, j)
@@ -162,18 +181,130 @@ func main() {
 		baseType.Path = strings.Replace(baseType.Path, "{{TypeLow}}", tl, -1)
 		baseType.Type = strings.Replace(baseType.Type, "{{TypeLow}}", tl, -1)
 		baseType.StructPrefixLow = strings.Replace(baseType.StructPrefixLow, "{{TypeLow}}", tl, -1)
+		baseType.NewSuffix = strings.Replace(baseType.NewSuffix, "{{Type}}", t, -1)
 
 		baseTypeDesc.StructPrefix = strings.Replace(baseTypeDesc.StructPrefix, "{{Type}}", t, -1)
 		baseTypeDesc.Name = strings.Replace(baseTypeDesc.Name, "{{TypeLow}}", tl, -1)
 		baseTypeDesc.Path = strings.Replace(baseTypeDesc.Path, "{{TypeLow}}", tl, -1)
 		baseTypeDesc.Type = strings.Replace(baseTypeDesc.Type, "{{TypeLow}}", tl, -1)
 		baseTypeDesc.StructPrefixLow = strings.Replace(baseTypeDesc.StructPrefixLow, "{{TypeLow}}", tl, -1)
+		baseTypeDesc.NewSuffix = strings.Replace(baseTypeDesc.NewSuffix, "{{Type}}", t, -1)
+
+		baseType.Integer = t != "String"
+		baseTypeDesc.Integer = baseType.Integer
 
 		generate(baseType)
 		generate(baseTypeDesc)
 	}
+
+	// For NewMulti.
+	basemulti := &Variant{
+		Package:         "skipset",
+		Name:            "multi",
+		Path:            "gen_multi.go",
+		Imports:         "\"fmt\"\n\"io\"\n\"sort\"\n\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
+		Type:            "T",
+		TypeArgument:    "[T]",
+		TypeParam:       "[T ordered]",
+		Multiset:        true,
+		StructPrefix:    "Multi",
+		StructPrefixLow: "multi",
+		StructSuffix:    "",
+		NewSuffix:       "Multi",
+		Funcs: template.FuncMap{
+			"Less": func(i, j string) string {
+				return fmt.Sprintf("(%s < %s)", i, j)
+			},
+			"Equal": func(i, j string) string {
+				return fmt.Sprintf("%s == %s", i, j)
+			},
+		},
+	}
+	generate(basemulti)
+	basemulti.Name += "Desc"
+	basemulti.StructSuffix += "Desc"
+	basemulti.NewSuffix += "Desc"
+	basemulti.Path = "gen_multidesc.go"
+	basemulti.Funcs = template.FuncMap{
+		"Less": func(i, j string) string {
+			return fmt.Sprintf("(%s > %s)", i, j)
+		},
+		"Equal": func(i, j string) string {
+			return fmt.Sprintf("%s == %s", i, j)
+		},
+	}
+	generate(basemulti)
+
+	// For NewFuncMulti.
+	generate(&Variant{
+		Package:         "skipset",
+		Name:            "funcMulti",
+		Path:            "gen_funcmulti.go",
+		Imports:         "\"fmt\"\n\"io\"\n\"sort\"\n\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
+		Type:            "T",
+		TypeArgument:    "[T]",
+		TypeParam:       "[T any]",
+		ExtraFileds:     "\nless func(a,b T)bool\n",
+		HasLess:         true,
+		Multiset:        true,
+		StructPrefix:    "FuncMulti",
+		StructPrefixLow: "funcmulti",
+		StructSuffix:    "",
+		NewSuffix:       "FuncMulti",
+		Funcs: template.FuncMap{
+			"Less": func(i, j string) string {
+				return fmt.Sprintf("s.less(%s,%s)", i, j)
+			},
+			"Equal": func(i, j string) string {
+				return fmt.Sprintf("!s.less(%s,%s)", j, i)
+			},
+		},
+	})
+
+	// For NewStringMulti.
+	basestringmulti := &Variant{
+		Package:         "skipset",
+		Name:            "stringMulti",
+		Path:            "gen_stringmulti.go",
+		Imports:         "\"fmt\"\n\"io\"\n\"sort\"\n\"sync\"\n\"sync/atomic\"\n\"unsafe\"\n",
+		Type:            "string",
+		TypeArgument:    "",
+		TypeParam:       "",
+		Multiset:        true,
+		StructPrefix:    "StringMulti",
+		StructPrefixLow: "stringmulti",
+		StructSuffix:    "",
+		NewSuffix:       "StringMulti",
+		Funcs: template.FuncMap{
+			"Less": func(i, j string) string {
+				return fmt.Sprintf("(%s < %s)", i, j)
+			},
+			"Equal": func(i, j string) string {
+				return fmt.Sprintf("%s == %s", i, j)
+			},
+		},
+	}
+	generate(basestringmulti)
+	basestringmulti.Name += "Desc"
+	basestringmulti.StructSuffix += "Desc"
+	basestringmulti.NewSuffix += "Desc"
+	basestringmulti.Path = "gen_stringmultidesc.go"
+	basestringmulti.Funcs = template.FuncMap{
+		"Less": func(i, j string) string {
+			return fmt.Sprintf("(%s > %s)", i, j)
+		},
+		"Equal": func(i, j string) string {
+			return fmt.Sprintf("%s == %s", i, j)
+		},
+	}
+	generate(basestringmulti)
+
+	generateSlog(generated)
 }
 
+// generated is the list of the variants generated by generate.
+var generated []Variant
+
 // generate generates the code for variant `v` into a file named by `v.Path`.
 func generate(v *Variant) {
 	// Parse templateCode anew for each variant because Parse requires Funcs to be
@@ -199,7 +330,35 @@ func generate(v *Variant) {
 	if err := os.WriteFile(v.Path, formatted, 0644); err != nil {
 		log.Fatal("WriteFile:", err)
 	}
+	generated = append(generated, *v)
+}
+
+// generateSlog generates the code for the log/slog support of all the variants into gen_slog.go,
+// which requires go1.21.
+func generateSlog(variants []Variant) {
+	tmpl, err := template.New("slog").Parse(slogTemplateCode)
+	if err != nil {
+		log.Fatal("template Parse:", err)
+	}
+
+	var out bytes.Buffer
+	err = tmpl.Execute(&out, variants)
+	if err != nil {
+		log.Fatal("template Execute:", err)
+	}
+
+	formatted, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatal("format:", err)
+	}
+
+	if err := os.WriteFile("gen_slog.go", formatted, 0644); err != nil {
+		log.Fatal("WriteFile:", err)
+	}
 }
 
 //go:embed skipset.tpl
 var templateCode string
+
+//go:embed slog.tpl
+var slogTemplateCode string
@@ -3,6 +3,10 @@
 package skipset
 
 import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -13,8 +17,9 @@ type FuncSet[T any] struct {
 	length       int64
 	highestLevel uint64 // highest level for now
 	header       *funcnode[T]
-
-	less func(a, b T) bool
+	notify       unsafe.Pointer // *notifier, stored by the first follower
+	pending      unsafe.Pointer // *lengthCounter, stored by SplitAt until the length is counted
+	less         func(a, b T) bool
 }
 
 type funcnode[T any] struct {
@@ -52,6 +57,15 @@ func (n *funcnode[T]) atomicStoreNext(i int, next *funcnode[T]) {
 	n.next.atomicStore(i, unsafe.Pointer(next))
 }
 
+// atomicLoadNextValid returns the first fully linked and unmarked node after n in level 0.
+func (n *funcnode[T]) atomicLoadNextValid() *funcnode[T] {
+	x := n.atomicLoadNext(0)
+	for x != nil && !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
 // findNodeRemove takes a value and two maximal-height arrays then searches exactly as in a sequential skip-list.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
 func (s *FuncSet[T]) findNodeRemove(value T, preds *[maxLevel]*funcnode[T], succs *[maxLevel]*funcnode[T]) int {
@@ -95,6 +109,119 @@ func (s *FuncSet[T]) findNodeAdd(value T, preds *[maxLevel]*funcnode[T], succs *
 	return -1
 }
 
+// newEmpty returns an empty skip set in the same order as s.
+func (s *FuncSet[T]) newEmpty() *FuncSet[T] {
+	return NewFunc[T](s.less)
+}
+
+// funcbuilder appends values to a skip set in linear time.
+// The skip set must not be accessed by other goroutines until the build is done.
+type funcbuilder[T any] struct {
+	s    *FuncSet[T]
+	tail [maxLevel]*funcnode[T] // the last node before or at the last appended value in each level
+}
+
+func (s *FuncSet[T]) newBuilder() *funcbuilder[T] {
+	b := &funcbuilder[T]{s: s}
+	for i := range b.tail {
+		b.tail[i] = s.header
+	}
+	return b
+}
+
+// append adds the value right after the last appended value, the value must be greater than
+// it and less than the next value in the skip set (in the order of the skip set).
+func (b *funcbuilder[T]) append(value T) {
+	level := b.s.randomlevel()
+	nn := newFuncNode(value, level)
+	for i := 0; i < level; i++ {
+		nn.storeNext(i, b.tail[i].loadNext(i))
+		b.tail[i].storeNext(i, nn)
+		b.tail[i] = nn
+	}
+	nn.flags.SetTrue(fullyLinked)
+	b.s.length++
+}
+
+// insert adds the value wherever it belongs in the skip set, it returns false if the value is
+// already in the skip set.
+// The search starts from the last appended value, so inserting values in the order of the skip set
+// takes linear time.
+func (b *funcbuilder[T]) insert(value T) bool {
+	s, last := b.s, b.tail[0]
+	if next := last.loadNext(0); (last != s.header && !s.less(last.value, value)) || (next != nil && !s.less(value, next.value)) {
+		// The value doesn't follow the last appended value, search it from the header.
+		for i := range b.tail {
+			b.tail[i] = s.header
+		}
+		var succs [maxLevel]*funcnode[T]
+		if lFound := s.findNodeAdd(value, &b.tail, &succs); lFound != -1 {
+			// The search stopped at level lFound, b.tail is only valid for the header.
+			for i := range b.tail {
+				b.tail[i] = s.header
+			}
+			return false
+		}
+	}
+	b.append(value)
+	return true
+}
+
+// reset replaces the values of s with the given ones, which are sorted in place then appended in linear time,
+// repeated values are added once.
+// It requires exclusive access, s must not be used by other goroutines during the call.
+func (s *FuncSet[T]) reset(values []T) {
+	s.countLength()
+	sort.Slice(values, func(i, j int) bool {
+		return s.less(values[i], values[j])
+	})
+	n := s.newEmpty()
+	b := n.newBuilder()
+	for i, v := range values {
+		if i > 0 && !s.less(values[i-1], v) {
+			continue
+		}
+		b.append(v)
+	}
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+}
+
+// NewFuncFromSorted returns a skip set with the given values, which must be in the order of
+// the skip set. Duplicate values are added once.
+// The skip list is built in linear time, with the same random levels as if the values were added
+// one by one. It returns ErrNotSorted if a value is out of order.
+func NewFuncFromSorted[T any](less func(a, b T) bool, values []T) (*FuncSet[T], error) {
+	s := NewFunc[T](less)
+	b := s.newBuilder()
+	for i, v := range values {
+		if i > 0 {
+			if s.less(v, values[i-1]) {
+				return nil, ErrNotSorted
+			}
+			if !s.less(values[i-1], v) {
+				continue
+			}
+		}
+		b.append(v)
+	}
+	return s, nil
+}
+
+// OfFunc returns a skip set with the given values in any order, see FromSliceFunc.
+func OfFunc[T any](less func(a, b T) bool, values ...T) *FuncSet[T] {
+	return FromSliceFunc(less, values)
+}
+
+// FromSliceFunc returns a skip set with the given values in any order,
+// duplicate values are added once.
+// A copy of the values is sorted in the order of the skip set, then the skip list is built
+// in linear time.
+func FromSliceFunc[T any](less func(a, b T) bool, values []T) *FuncSet[T] {
+	s := NewFunc[T](less)
+	s.reset(append([]T(nil), values...))
+	return s
+}
+
 func unlockfunc[T any](preds [maxLevel]*funcnode[T], highestLevel int) {
 	var prevPred *funcnode[T]
 	for i := highestLevel; i >= 0; i-- {
@@ -110,6 +237,7 @@ func unlockfunc[T any](preds [maxLevel]*funcnode[T], highestLevel int) {
 //
 // If the value is in the skip set but not fully linked, this process will wait until it is.
 func (s *FuncSet[T]) Add(value T) bool {
+	s.countLength()
 	level := s.randomlevel()
 	var preds, succs [maxLevel]*funcnode[T]
 	for {
@@ -159,6 +287,9 @@ func (s *FuncSet[T]) Add(value T) bool {
 		nn.flags.SetTrue(fullyLinked)
 		unlockfunc(preds, highestLocked)
 		atomic.AddInt64(&s.length, 1)
+		if n := loadNotifier(&s.notify); n != nil {
+			n.broadcast()
+		}
 		return true
 	}
 }
@@ -199,6 +330,7 @@ func (s *FuncSet[T]) Contains(value T) bool {
 
 // Remove removes a node from the skip set.
 func (s *FuncSet[T]) Remove(value T) bool {
+	s.countLength()
 	var (
 		nodeToRemove *funcnode[T]
 		isMarked     bool // represents if this operation mark the node
@@ -309,5 +441,594 @@ func (s *FuncSet[T]) RangeFrom(start T, f func(value T) bool) {
 
 // Len returns the length of this skip set.
 func (s *FuncSet[T]) Len() int {
+	s.countLength()
 	return int(atomic.LoadInt64(&s.length))
 }
+
+// last returns the last value in the skip set, it returns false if the skip set is empty.
+func (s *FuncSet[T]) last() (T, bool) {
+	// Only step onto valid nodes in the upper levels, so that x is never after the last valid node.
+	x := s.header
+	for i := int(atomic.LoadUint64(&s.highestLevel)) - 1; i > 0; i-- {
+		for next := x.atomicLoadNext(i); next != nil && next.flags.MGet(fullyLinked|marked, fullyLinked); next = x.atomicLoadNext(i) {
+			x = next
+		}
+	}
+	last := x
+	for x = x.atomicLoadNextValid(); x != nil; x = x.atomicLoadNextValid() {
+		last = x
+	}
+	if last == s.header {
+		var zero T
+		return zero, false
+	}
+	return last.value, true
+}
+
+// Follow calls f sequentially for all values with `value >= start` in the skip set, like RangeFrom,
+// then waits for values greater than the last value passed to f and calls f for them as they are
+// added, like `tail -f`. Values added behind the last value passed to f are skipped.
+//
+// Follow returns nil once f returns false, or ctx.Err() once ctx is done. It does not spin while
+// waiting: Add wakes up the followers after inserting a value.
+func (s *FuncSet[T]) Follow(ctx context.Context, start T, f func(value T) bool) error {
+	var (
+		n       = loadOrStoreNotifier(&s.notify)
+		cursor  = start
+		started bool // cursor has been passed to f
+		stopped bool
+	)
+	next := func(value T) bool {
+		if started && !s.less(cursor, value) {
+			return true
+		}
+		cursor, started = value, true
+		if !f(value) {
+			stopped = true
+			return false
+		}
+		return true
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Get the channel before scanning, so that a value added after the scan
+		// always closes it.
+		ch := n.wait()
+		s.RangeFrom(cursor, next)
+		if stopped {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Quantile returns the q-quantile (0 <= q <= 1) of the skip set, that is the element whose
+// rank in the skip set is ceil(q*n) for n elements. Quantile(0) and Quantile(1) are always
+// exactly the first and the last element. It returns false if the skip set is empty or q is
+// out of range.
+//
+// The skip set does not maintain span counts, so only sets with fewer than 4096 elements
+// are walked at level 0 and get an exact answer for every q. Larger sets are estimated from the highest
+// level expected to hold at least 1024 nodes: every element reaches that level independently
+// with the same probability, so the sampled quantile is off by at most about
+// sqrt(q*(1-q)/1024) (1.6% of the rank range for the median) in one standard deviation.
+func (s *FuncSet[T]) Quantile(q float64) (T, bool) {
+	res, ok := s.Quantiles(q)
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return res[0], true
+}
+
+// Quantiles is like Quantile but estimates several quantiles with a single scan.
+// The results are in the same order as qs. It returns false if the skip set is empty
+// or any q is out of range.
+func (s *FuncSet[T]) Quantiles(qs ...float64) ([]T, bool) {
+	level := quantileLevel(s.Len(), int(atomic.LoadUint64(&s.highestLevel)))
+	var count int
+	for x := s.header.atomicLoadNext(level); x != nil; x = x.atomicLoadNext(level) {
+		if x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			count++
+		}
+	}
+	ranks, order, ok := quantileRanks(qs, count)
+	if !ok || count == 0 {
+		return nil, false
+	}
+	var (
+		res   = make([]T, len(qs))
+		i     int
+		rank  int
+		last  T
+		found bool
+	)
+	for x := s.header.atomicLoadNext(level); x != nil && i < len(order); x = x.atomicLoadNext(level) {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			continue
+		}
+		for i < len(order) && ranks[order[i]] == rank {
+			res[order[i]] = x.value
+			i++
+		}
+		last, found = x.value, true
+		rank++
+	}
+	if i < len(order) {
+		// Some elements have been removed since counting.
+		if !found {
+			return nil, false
+		}
+		for ; i < len(order); i++ {
+			res[order[i]] = last
+		}
+	}
+	if level != 0 {
+		// The endpoints are cheap to find exactly: the first node of level 0 and the tail.
+		for i, q := range qs {
+			switch q {
+			case 0:
+				if x := s.header.atomicLoadNextValid(); x != nil {
+					res[i] = x.value
+				}
+			case 1:
+				if v, ok := s.last(); ok {
+					res[i] = v
+				}
+			}
+		}
+	}
+	return res, true
+}
+
+// MergeFunc returns a function that calls f sequentially for each value present in
+// any of the skip sets, in the order of the skip sets. Values present in several skip sets are
+// passed to f only once. If f returns false, the iteration stops.
+//
+// The level-0 lists of the skip sets are walked in lockstep without copying any data, so the
+// iteration observes concurrent modifications the same way Range does.
+//
+// All skip sets must share the same ordering, the less function of the first one is used.
+func MergeFunc[T any](sets ...*FuncSet[T]) func(f func(value T) bool) {
+	return func(f func(value T) bool) {
+		if len(sets) != 0 {
+			sets[0].rangeMerge(sets, f)
+		}
+	}
+}
+
+// rangeMerge calls f for each distinct value of sets, using the ordering of s.
+func (s *FuncSet[T]) rangeMerge(sets []*FuncSet[T], f func(value T) bool) {
+	h := mergeHeap[*funcnode[T]]{
+		nodes: make([]*funcnode[T], 0, len(sets)),
+		less: func(a, b *funcnode[T]) bool {
+			return s.less(a.value, b.value)
+		},
+	}
+	for _, set := range sets {
+		if x := set.header.atomicLoadNextValid(); x != nil {
+			h.push(x)
+		}
+	}
+	var (
+		last    T
+		emitted bool
+	)
+	for len(h.nodes) != 0 {
+		x := h.nodes[0]
+		if !emitted || s.less(last, x.value) {
+			if !f(x.value) {
+				return
+			}
+			last, emitted = x.value, true
+		}
+		if next := x.atomicLoadNextValid(); next != nil {
+			h.nodes[0] = next
+			h.fix()
+		} else {
+			h.pop()
+		}
+	}
+}
+
+// UnionFunc returns a new skip set with the values present in a or b.
+//
+// UnionFunc, IntersectFunc, DifferenceFunc and SymmetricDifferenceFunc merge-walk
+// the level-0 lists of both skip sets and build the result in linear time. The skip sets
+// may be modified concurrently, in which case the result observes the modifications the
+// same way Range does.
+// The result uses the less function of a.
+func UnionFunc[T any](a, b *FuncSet[T]) *FuncSet[T] {
+	return a.combine(b, true, true, true)
+}
+
+// IntersectFunc returns a new skip set with the values present in both a and b.
+func IntersectFunc[T any](a, b *FuncSet[T]) *FuncSet[T] {
+	return a.combine(b, false, true, false)
+}
+
+// DifferenceFunc returns a new skip set with the values present in a but not in b.
+func DifferenceFunc[T any](a, b *FuncSet[T]) *FuncSet[T] {
+	return a.combine(b, true, false, false)
+}
+
+// SymmetricDifferenceFunc returns a new skip set with the values present in exactly one of a and b.
+func SymmetricDifferenceFunc[T any](a, b *FuncSet[T]) *FuncSet[T] {
+	return a.combine(b, true, false, true)
+}
+
+// combine merge-walks s and other, the result contains the values only in s if onlyS is true,
+// the values in both if both is true, and the values only in other if onlyOther is true.
+func (s *FuncSet[T]) combine(other *FuncSet[T], onlyS, both, onlyOther bool) *FuncSet[T] {
+	res := s.newEmpty()
+	b := res.newBuilder()
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for (x != nil || onlyOther) && (y != nil || onlyS) && (x != nil || y != nil) {
+		switch {
+		case y == nil || x != nil && s.less(x.value, y.value):
+			if onlyS {
+				b.append(x.value)
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || s.less(y.value, x.value):
+			if onlyOther {
+				b.append(y.value)
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			if both {
+				b.append(x.value)
+			}
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return res
+}
+
+// EqualFunc reports whether a and b contain the same values.
+//
+// EqualFunc, IsSubsetFunc, IsSupersetFunc and DisjointFunc merge-walk the level-0 lists
+// of both skip sets and return as soon as the answer is known. They compare the lengths first
+// when it can decide the answer, so if the skip sets are modified concurrently the result may
+// reflect any state of them during the call.
+func EqualFunc[T any](a, b *FuncSet[T]) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	return a.isSubset(b, true)
+}
+
+// IsSubsetFunc reports whether every value of a is in b.
+func IsSubsetFunc[T any](a, b *FuncSet[T]) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	return a.isSubset(b, false)
+}
+
+// IsSupersetFunc reports whether every value of b is in a.
+func IsSupersetFunc[T any](a, b *FuncSet[T]) bool {
+	return IsSubsetFunc(b, a)
+}
+
+// DisjointFunc reports whether a and b have no value in common.
+func DisjointFunc[T any](a, b *FuncSet[T]) bool {
+	if a.Len() == 0 || b.Len() == 0 {
+		return true
+	}
+	return a.disjoint(b)
+}
+
+// disjoint reports whether s and other have no value in common.
+func (s *FuncSet[T]) disjoint(other *FuncSet[T]) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil && y != nil {
+		switch {
+		case s.less(x.value, y.value):
+			x = x.atomicLoadNextValid()
+		case s.less(y.value, x.value):
+			y = y.atomicLoadNextValid()
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isSubset reports whether every value of s is in other, and if equal is true,
+// whether every value of other is in s as well.
+func (s *FuncSet[T]) isSubset(other *FuncSet[T], equal bool) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil {
+		switch {
+		case y == nil || s.less(x.value, y.value):
+			return false // x is not in other
+		case s.less(y.value, x.value):
+			if equal {
+				return false // y is not in s
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return !equal || y == nil
+}
+
+// DiffFunc reports the changes from oldSet to newSet in a single merge-walk of both
+// skip sets: onAdded is called for each value only in newSet and onRemoved for each value only
+// in oldSet, sequentially and in the order of the skip sets. A nil callback ignores its kind of
+// change. If a callback returns false, the iteration stops.
+func DiffFunc[T any](oldSet, newSet *FuncSet[T], onAdded, onRemoved func(value T) bool) {
+	oldSet.diff(newSet, func(value T, added bool) bool {
+		if added {
+			return onAdded == nil || onAdded(value)
+		}
+		return onRemoved == nil || onRemoved(value)
+	})
+}
+
+// DiffSeqFunc is like DiffFunc but returns the changes as an iterator of
+// (value, added) pairs, where added is false for the values removed from oldSet.
+func DiffSeqFunc[T any](oldSet, newSet *FuncSet[T]) func(yield func(value T, added bool) bool) {
+	return func(yield func(value T, added bool) bool) {
+		oldSet.diff(newSet, yield)
+	}
+}
+
+// diff calls yield for each value only in s (added is false) or only in other (added is true).
+func (s *FuncSet[T]) diff(other *FuncSet[T], yield func(value T, added bool) bool) {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil || y != nil {
+		switch {
+		case y == nil || x != nil && s.less(x.value, y.value):
+			if !yield(x.value, false) {
+				return
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || s.less(y.value, x.value):
+			if !yield(y.value, true) {
+				return
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+}
+
+// SplitAt moves all the values with `value >= k` (in the order of the skip set) into a new
+// skip set and returns it. The towers are cut at the split point in O(log n) time. The lengths
+// of the two parts are counted later, by the first call to Len or to a method modifying either
+// part, which walks the shorter part.
+//
+// SplitAt requires exclusive access, s must not be used by other goroutines during the call.
+func (s *FuncSet[T]) SplitAt(k T) *FuncSet[T] {
+	s.countLength()
+	var preds, succs [maxLevel]*funcnode[T]
+	s.findNodeRemove(k, &preds, &succs)
+	right := s.newEmpty()
+	highestLevel := atomic.LoadUint64(&s.highestLevel)
+	for i := 0; i < int(highestLevel); i++ {
+		right.header.atomicStoreNext(i, succs[i])
+		preds[i].atomicStoreNext(i, nil)
+	}
+	atomic.StoreUint64(&right.highestLevel, highestLevel)
+
+	total := atomic.LoadInt64(&s.length)
+	c := &lengthCounter{count: func() {
+		// Count the shorter part by walking both parts in lockstep, neither part has been
+		// modified since the split.
+		var (
+			n    int64
+			l, r = s.header.loadNext(0), right.header.loadNext(0)
+		)
+		for l != nil && r != nil {
+			l, r = l.loadNext(0), r.loadNext(0)
+			n++
+		}
+		if r == nil {
+			atomic.StoreInt64(&right.length, n)
+			atomic.StoreInt64(&s.length, total-n)
+		} else {
+			atomic.StoreInt64(&right.length, total-n)
+			atomic.StoreInt64(&s.length, n)
+		}
+		atomic.StorePointer(&s.pending, nil)
+		atomic.StorePointer(&right.pending, nil)
+	}}
+	atomic.StorePointer(&s.pending, unsafe.Pointer(c))
+	atomic.StorePointer(&right.pending, unsafe.Pointer(c))
+	return right
+}
+
+// countLength counts the length of s if it was left pending by SplitAt, it must be called
+// before reading or modifying the length.
+func (s *FuncSet[T]) countLength() {
+	if c := (*lengthCounter)(atomic.LoadPointer(&s.pending)); c != nil {
+		c.once.Do(c.count)
+	}
+}
+
+// Join moves all the values of right to the end of s in O(log n) time, leaving right empty.
+// The lengths left pending by SplitAt, if any, are counted first.
+// It returns false and does nothing if the first value of right is not greater than the last
+// value of s (in the order of the skip set).
+//
+// Join requires exclusive access, neither s nor right may be used by other goroutines during the call.
+func (s *FuncSet[T]) Join(right *FuncSet[T]) bool {
+	first := right.header.loadNext(0)
+	if first == nil {
+		return true
+	}
+	if right == s {
+		return false
+	}
+	s.countLength()
+	right.countLength()
+	// Find the last node in each level of s.
+	var (
+		tails [maxLevel]*funcnode[T]
+		x     = s.header
+	)
+	for i := maxLevel - 1; i >= 0; i-- {
+		for next := x.loadNext(i); next != nil; next = x.loadNext(i) {
+			x = next
+		}
+		tails[i] = x
+	}
+	if x != s.header && !s.less(x.value, first.value) {
+		return false
+	}
+	rightLevel := atomic.LoadUint64(&right.highestLevel)
+	for i := 0; i < int(rightLevel); i++ {
+		tails[i].atomicStoreNext(i, right.header.loadNext(i))
+		right.header.atomicStoreNext(i, nil)
+	}
+	if rightLevel > atomic.LoadUint64(&s.highestLevel) {
+		atomic.StoreUint64(&s.highestLevel, rightLevel)
+	}
+	atomic.AddInt64(&s.length, atomic.SwapInt64(&right.length, 0))
+	return true
+}
+
+// MarshalJSON implements json.Marshaler. The skip set is encoded as a JSON array of its values
+// in the order of the skip set.
+func (s *FuncSet[T]) MarshalJSON() ([]byte, error) {
+	if s.header == nil {
+		return []byte("[]"), nil
+	}
+	return marshalJSON(s.Range)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The values of the JSON array replace the values
+// of the skip set.
+// The less function can't be decoded, so the skip set must be created by NewFunc before
+// unmarshalling, or an error is returned.
+//
+// UnmarshalJSON requires exclusive access, s must not be used by other goroutines during the call.
+func (s *FuncSet[T]) UnmarshalJSON(data []byte) error {
+	if s.less == nil {
+		return errNoLess
+	}
+	if string(data) == "null" {
+		return nil
+	}
+	values, err := unmarshalJSON[T](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. The skip set is encoded as a slice of its values
+// in the order of the skip set.
+func (s *FuncSet[T]) GobEncode() ([]byte, error) {
+	if s.header == nil {
+		return gobEncode[T](nil)
+	}
+	return gobEncode(s.Range)
+}
+
+// GobDecode implements gob.GobDecoder. The decoded values replace the values of the skip set.
+// The less function can't be decoded, so the skip set must be created by NewFunc before
+// decoding, e.g. by setting the field of a struct before decoding the struct, or an error is returned.
+//
+// GobDecode requires exclusive access, s must not be used by other goroutines during the call.
+func (s *FuncSet[T]) GobDecode(data []byte) error {
+	if s.less == nil {
+		return errNoLess
+	}
+	values, err := gobDecode[T](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// WriteTo implements io.WriterTo, it writes the values to w like WriteToCodec with the default
+// codec of the value type: integers are encoded as varints, floats as their IEEE 754 bits,
+// strings as their length followed by their bytes. Other types require WriteToCodec.
+func (s *FuncSet[T]) WriteTo(w io.Writer) (int64, error) {
+	codec, err := defaultCodec[T]()
+	if err != nil {
+		return 0, err
+	}
+	return s.WriteToCodec(w, codec)
+}
+
+// WriteToCodec writes the values to w in the order of the skip set
+// as they are visited by Range.
+// The values are streamed without buffering the whole skip set, each one is encoded by codec
+// after a 1 byte, and a 0 byte marks the end of the stream.
+func (s *FuncSet[T]) WriteToCodec(w io.Writer, codec Codec[T]) (int64, error) {
+	if s.header == nil {
+		return writeTo(w, codec, nil)
+	}
+	return writeTo(w, codec, s.Range)
+}
+
+// ReadFrom implements io.ReaderFrom, it reads values from r like ReadFromCodec with the
+// default codec of the value type, see WriteTo.
+func (s *FuncSet[T]) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := defaultCodec[T]()
+	if err != nil {
+		return 0, err
+	}
+	return s.ReadFromCodec(r, codec)
+}
+
+// ReadFromCodec reads the values written by WriteToCodec from r and adds them into the skip set,
+// it returns the number of bytes read. It stops right after the end of the stream, so several
+// skip sets can be read back-to-back from r. Unless r implements io.ByteReader, it is read one
+// byte at a time, wrap it in a bufio.Reader for speed.
+// Each value is inserted by searching from the previous one, so reading values in the order of the
+// skip set takes linear time.
+// The less function can't be decoded, so the skip set must be created by NewFunc before
+// reading, or an error is returned.
+//
+// ReadFromCodec requires exclusive access, s must not be used by other goroutines during the call.
+func (s *FuncSet[T]) ReadFromCodec(r io.Reader, codec Codec[T]) (int64, error) {
+	if s.less == nil {
+		return 0, errNoLess
+	}
+	if s.header == nil {
+		n := s.newEmpty()
+		s.header, s.highestLevel = n.header, n.highestLevel
+	}
+	s.countLength()
+	b := s.newBuilder()
+	return readFrom(r, codec, func(value T) {
+		b.insert(value)
+	})
+}
+
+// String returns the values in the order of the skip set, e.g. {1, 2, 3}.
+// At most 16 values are shown, see Format.
+func (s *FuncSet[T]) String() string {
+	return fmt.Sprint(s)
+}
+
+// Format implements fmt.Formatter. The values are formatted with the verb in the order of the
+// skip set, e.g. {1, 2, 3}.
+// At most 16 values are shown, the precision sets another maximum, e.g. %.100v.
+// The plus flag adds the length, e.g. len=3 {1, 2, 3}.
+func (s *FuncSet[T]) Format(f fmt.State, verb rune) {
+	if s.header == nil {
+		formatSet(f, verb, 0, nil)
+		return
+	}
+	formatSet(f, verb, s.Len(), func(emit func(value any, count int) bool) {
+		s.Range(func(value T) bool {
+			return emit(value, -1)
+		})
+	})
+}
@@ -3,6 +3,11 @@
 package skipset
 
 import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -13,6 +18,8 @@ type Int32Set struct {
 	length       int64
 	highestLevel uint64 // highest level for now
 	header       *int32node
+	notify       unsafe.Pointer // *notifier, stored by the first follower
+	pending      unsafe.Pointer // *lengthCounter, stored by SplitAt until the length is counted
 }
 
 type int32node struct {
@@ -50,6 +57,15 @@ func (n *int32node) atomicStoreNext(i int, next *int32node) {
 	n.next.atomicStore(i, unsafe.Pointer(next))
 }
 
+// atomicLoadNextValid returns the first fully linked and unmarked node after n in level 0.
+func (n *int32node) atomicLoadNextValid() *int32node {
+	x := n.atomicLoadNext(0)
+	for x != nil && !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
 // findNodeRemove takes a value and two maximal-height arrays then searches exactly as in a sequential skip-list.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
 func (s *Int32Set) findNodeRemove(value int32, preds *[maxLevel]*int32node, succs *[maxLevel]*int32node) int {
@@ -93,6 +109,119 @@ func (s *Int32Set) findNodeAdd(value int32, preds *[maxLevel]*int32node, succs *
 	return -1
 }
 
+// newEmpty returns an empty skip set in the same order as s.
+func (s *Int32Set) newEmpty() *Int32Set {
+	return NewInt32()
+}
+
+// int32builder appends values to a skip set in linear time.
+// The skip set must not be accessed by other goroutines until the build is done.
+type int32builder struct {
+	s    *Int32Set
+	tail [maxLevel]*int32node // the last node before or at the last appended value in each level
+}
+
+func (s *Int32Set) newBuilder() *int32builder {
+	b := &int32builder{s: s}
+	for i := range b.tail {
+		b.tail[i] = s.header
+	}
+	return b
+}
+
+// append adds the value right after the last appended value, the value must be greater than
+// it and less than the next value in the skip set (in the order of the skip set).
+func (b *int32builder) append(value int32) {
+	level := b.s.randomlevel()
+	nn := newInt32Node(value, level)
+	for i := 0; i < level; i++ {
+		nn.storeNext(i, b.tail[i].loadNext(i))
+		b.tail[i].storeNext(i, nn)
+		b.tail[i] = nn
+	}
+	nn.flags.SetTrue(fullyLinked)
+	b.s.length++
+}
+
+// insert adds the value wherever it belongs in the skip set, it returns false if the value is
+// already in the skip set.
+// The search starts from the last appended value, so inserting values in the order of the skip set
+// takes linear time.
+func (b *int32builder) insert(value int32) bool {
+	s, last := b.s, b.tail[0]
+	if next := last.loadNext(0); (last != s.header && !(last.value < value)) || (next != nil && !(value < next.value)) {
+		// The value doesn't follow the last appended value, search it from the header.
+		for i := range b.tail {
+			b.tail[i] = s.header
+		}
+		var succs [maxLevel]*int32node
+		if lFound := s.findNodeAdd(value, &b.tail, &succs); lFound != -1 {
+			// The search stopped at level lFound, b.tail is only valid for the header.
+			for i := range b.tail {
+				b.tail[i] = s.header
+			}
+			return false
+		}
+	}
+	b.append(value)
+	return true
+}
+
+// reset replaces the values of s with the given ones, which are sorted in place then appended in linear time,
+// repeated values are added once.
+// It requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) reset(values []int32) {
+	s.countLength()
+	sort.Slice(values, func(i, j int) bool {
+		return (values[i] < values[j])
+	})
+	n := s.newEmpty()
+	b := n.newBuilder()
+	for i, v := range values {
+		if i > 0 && v == values[i-1] {
+			continue
+		}
+		b.append(v)
+	}
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+}
+
+// NewInt32FromSorted returns a skip set with the given values, which must be in the order of
+// the skip set. Duplicate values are added once.
+// The skip list is built in linear time, with the same random levels as if the values were added
+// one by one. It returns ErrNotSorted if a value is out of order.
+func NewInt32FromSorted(values []int32) (*Int32Set, error) {
+	s := NewInt32()
+	b := s.newBuilder()
+	for i, v := range values {
+		if i > 0 {
+			if v < values[i-1] {
+				return nil, ErrNotSorted
+			}
+			if !(values[i-1] < v) {
+				continue
+			}
+		}
+		b.append(v)
+	}
+	return s, nil
+}
+
+// OfInt32 returns a skip set with the given values in any order, see FromSliceInt32.
+func OfInt32(values ...int32) *Int32Set {
+	return FromSliceInt32(values)
+}
+
+// FromSliceInt32 returns a skip set with the given values in any order,
+// duplicate values are added once.
+// A copy of the values is sorted in the order of the skip set, then the skip list is built
+// in linear time.
+func FromSliceInt32(values []int32) *Int32Set {
+	s := NewInt32()
+	s.reset(append([]int32(nil), values...))
+	return s
+}
+
 func unlockint32(preds [maxLevel]*int32node, highestLevel int) {
 	var prevPred *int32node
 	for i := highestLevel; i >= 0; i-- {
@@ -108,6 +237,7 @@ func unlockint32(preds [maxLevel]*int32node, highestLevel int) {
 //
 // If the value is in the skip set but not fully linked, this process will wait until it is.
 func (s *Int32Set) Add(value int32) bool {
+	s.countLength()
 	level := s.randomlevel()
 	var preds, succs [maxLevel]*int32node
 	for {
@@ -157,6 +287,9 @@ func (s *Int32Set) Add(value int32) bool {
 		nn.flags.SetTrue(fullyLinked)
 		unlockint32(preds, highestLocked)
 		atomic.AddInt64(&s.length, 1)
+		if n := loadNotifier(&s.notify); n != nil {
+			n.broadcast()
+		}
 		return true
 	}
 }
@@ -197,6 +330,7 @@ func (s *Int32Set) Contains(value int32) bool {
 
 // Remove removes a node from the skip set.
 func (s *Int32Set) Remove(value int32) bool {
+	s.countLength()
 	var (
 		nodeToRemove *int32node
 		isMarked     bool // represents if this operation mark the node
@@ -307,5 +441,666 @@ func (s *Int32Set) RangeFrom(start int32, f func(value int32) bool) {
 
 // Len returns the length of this skip set.
 func (s *Int32Set) Len() int {
+	s.countLength()
 	return int(atomic.LoadInt64(&s.length))
 }
+
+// last returns the last value in the skip set, it returns false if the skip set is empty.
+func (s *Int32Set) last() (int32, bool) {
+	// Only step onto valid nodes in the upper levels, so that x is never after the last valid node.
+	x := s.header
+	for i := int(atomic.LoadUint64(&s.highestLevel)) - 1; i > 0; i-- {
+		for next := x.atomicLoadNext(i); next != nil && next.flags.MGet(fullyLinked|marked, fullyLinked); next = x.atomicLoadNext(i) {
+			x = next
+		}
+	}
+	last := x
+	for x = x.atomicLoadNextValid(); x != nil; x = x.atomicLoadNextValid() {
+		last = x
+	}
+	if last == s.header {
+		var zero int32
+		return zero, false
+	}
+	return last.value, true
+}
+
+// Follow calls f sequentially for all values with `value >= start` in the skip set, like RangeFrom,
+// then waits for values greater than the last value passed to f and calls f for them as they are
+// added, like `tail -f`. Values added behind the last value passed to f are skipped.
+//
+// Follow returns nil once f returns false, or ctx.Err() once ctx is done. It does not spin while
+// waiting: Add wakes up the followers after inserting a value.
+func (s *Int32Set) Follow(ctx context.Context, start int32, f func(value int32) bool) error {
+	var (
+		n       = loadOrStoreNotifier(&s.notify)
+		cursor  = start
+		started bool // cursor has been passed to f
+		stopped bool
+	)
+	next := func(value int32) bool {
+		if started && !(cursor < value) {
+			return true
+		}
+		cursor, started = value, true
+		if !f(value) {
+			stopped = true
+			return false
+		}
+		return true
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Get the channel before scanning, so that a value added after the scan
+		// always closes it.
+		ch := n.wait()
+		s.RangeFrom(cursor, next)
+		if stopped {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Quantile returns the q-quantile (0 <= q <= 1) of the skip set, that is the element whose
+// rank in the skip set is ceil(q*n) for n elements. Quantile(0) and Quantile(1) are always
+// exactly the first and the last element. It returns false if the skip set is empty or q is
+// out of range.
+//
+// The skip set does not maintain span counts, so only sets with fewer than 4096 elements
+// are walked at level 0 and get an exact answer for every q. Larger sets are estimated from the highest
+// level expected to hold at least 1024 nodes: every element reaches that level independently
+// with the same probability, so the sampled quantile is off by at most about
+// sqrt(q*(1-q)/1024) (1.6% of the rank range for the median) in one standard deviation.
+func (s *Int32Set) Quantile(q float64) (int32, bool) {
+	res, ok := s.Quantiles(q)
+	if !ok {
+		var zero int32
+		return zero, false
+	}
+	return res[0], true
+}
+
+// Quantiles is like Quantile but estimates several quantiles with a single scan.
+// The results are in the same order as qs. It returns false if the skip set is empty
+// or any q is out of range.
+func (s *Int32Set) Quantiles(qs ...float64) ([]int32, bool) {
+	level := quantileLevel(s.Len(), int(atomic.LoadUint64(&s.highestLevel)))
+	var count int
+	for x := s.header.atomicLoadNext(level); x != nil; x = x.atomicLoadNext(level) {
+		if x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			count++
+		}
+	}
+	ranks, order, ok := quantileRanks(qs, count)
+	if !ok || count == 0 {
+		return nil, false
+	}
+	var (
+		res   = make([]int32, len(qs))
+		i     int
+		rank  int
+		last  int32
+		found bool
+	)
+	for x := s.header.atomicLoadNext(level); x != nil && i < len(order); x = x.atomicLoadNext(level) {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			continue
+		}
+		for i < len(order) && ranks[order[i]] == rank {
+			res[order[i]] = x.value
+			i++
+		}
+		last, found = x.value, true
+		rank++
+	}
+	if i < len(order) {
+		// Some elements have been removed since counting.
+		if !found {
+			return nil, false
+		}
+		for ; i < len(order); i++ {
+			res[order[i]] = last
+		}
+	}
+	if level != 0 {
+		// The endpoints are cheap to find exactly: the first node of level 0 and the tail.
+		for i, q := range qs {
+			switch q {
+			case 0:
+				if x := s.header.atomicLoadNextValid(); x != nil {
+					res[i] = x.value
+				}
+			case 1:
+				if v, ok := s.last(); ok {
+					res[i] = v
+				}
+			}
+		}
+	}
+	return res, true
+}
+
+// MergeInt32 returns a function that calls f sequentially for each value present in
+// any of the skip sets, in the order of the skip sets. Values present in several skip sets are
+// passed to f only once. If f returns false, the iteration stops.
+//
+// The level-0 lists of the skip sets are walked in lockstep without copying any data, so the
+// iteration observes concurrent modifications the same way Range does.
+func MergeInt32(sets ...*Int32Set) func(f func(value int32) bool) {
+	return func(f func(value int32) bool) {
+		if len(sets) != 0 {
+			sets[0].rangeMerge(sets, f)
+		}
+	}
+}
+
+// rangeMerge calls f for each distinct value of sets, using the ordering of s.
+func (s *Int32Set) rangeMerge(sets []*Int32Set, f func(value int32) bool) {
+	h := mergeHeap[*int32node]{
+		nodes: make([]*int32node, 0, len(sets)),
+		less: func(a, b *int32node) bool {
+			return (a.value < b.value)
+		},
+	}
+	for _, set := range sets {
+		if x := set.header.atomicLoadNextValid(); x != nil {
+			h.push(x)
+		}
+	}
+	var (
+		last    int32
+		emitted bool
+	)
+	for len(h.nodes) != 0 {
+		x := h.nodes[0]
+		if !emitted || (last < x.value) {
+			if !f(x.value) {
+				return
+			}
+			last, emitted = x.value, true
+		}
+		if next := x.atomicLoadNextValid(); next != nil {
+			h.nodes[0] = next
+			h.fix()
+		} else {
+			h.pop()
+		}
+	}
+}
+
+// UnionInt32 returns a new skip set with the values present in a or b.
+//
+// UnionInt32, IntersectInt32, DifferenceInt32 and SymmetricDifferenceInt32 merge-walk
+// the level-0 lists of both skip sets and build the result in linear time. The skip sets
+// may be modified concurrently, in which case the result observes the modifications the
+// same way Range does.
+func UnionInt32(a, b *Int32Set) *Int32Set {
+	return a.combine(b, true, true, true)
+}
+
+// IntersectInt32 returns a new skip set with the values present in both a and b.
+func IntersectInt32(a, b *Int32Set) *Int32Set {
+	return a.combine(b, false, true, false)
+}
+
+// DifferenceInt32 returns a new skip set with the values present in a but not in b.
+func DifferenceInt32(a, b *Int32Set) *Int32Set {
+	return a.combine(b, true, false, false)
+}
+
+// SymmetricDifferenceInt32 returns a new skip set with the values present in exactly one of a and b.
+func SymmetricDifferenceInt32(a, b *Int32Set) *Int32Set {
+	return a.combine(b, true, false, true)
+}
+
+// combine merge-walks s and other, the result contains the values only in s if onlyS is true,
+// the values in both if both is true, and the values only in other if onlyOther is true.
+func (s *Int32Set) combine(other *Int32Set, onlyS, both, onlyOther bool) *Int32Set {
+	res := s.newEmpty()
+	b := res.newBuilder()
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for (x != nil || onlyOther) && (y != nil || onlyS) && (x != nil || y != nil) {
+		switch {
+		case y == nil || x != nil && (x.value < y.value):
+			if onlyS {
+				b.append(x.value)
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || (y.value < x.value):
+			if onlyOther {
+				b.append(y.value)
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			if both {
+				b.append(x.value)
+			}
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return res
+}
+
+// EqualInt32 reports whether a and b contain the same values.
+//
+// EqualInt32, IsSubsetInt32, IsSupersetInt32 and DisjointInt32 merge-walk the level-0 lists
+// of both skip sets and return as soon as the answer is known. They compare the lengths first
+// when it can decide the answer, so if the skip sets are modified concurrently the result may
+// reflect any state of them during the call.
+func EqualInt32(a, b *Int32Set) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	return a.isSubset(b, true)
+}
+
+// IsSubsetInt32 reports whether every value of a is in b.
+func IsSubsetInt32(a, b *Int32Set) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	return a.isSubset(b, false)
+}
+
+// IsSupersetInt32 reports whether every value of b is in a.
+func IsSupersetInt32(a, b *Int32Set) bool {
+	return IsSubsetInt32(b, a)
+}
+
+// DisjointInt32 reports whether a and b have no value in common.
+func DisjointInt32(a, b *Int32Set) bool {
+	if a.Len() == 0 || b.Len() == 0 {
+		return true
+	}
+	return a.disjoint(b)
+}
+
+// disjoint reports whether s and other have no value in common.
+func (s *Int32Set) disjoint(other *Int32Set) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil && y != nil {
+		switch {
+		case (x.value < y.value):
+			x = x.atomicLoadNextValid()
+		case (y.value < x.value):
+			y = y.atomicLoadNextValid()
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isSubset reports whether every value of s is in other, and if equal is true,
+// whether every value of other is in s as well.
+func (s *Int32Set) isSubset(other *Int32Set, equal bool) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil {
+		switch {
+		case y == nil || (x.value < y.value):
+			return false // x is not in other
+		case (y.value < x.value):
+			if equal {
+				return false // y is not in s
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return !equal || y == nil
+}
+
+// DiffInt32 reports the changes from oldSet to newSet in a single merge-walk of both
+// skip sets: onAdded is called for each value only in newSet and onRemoved for each value only
+// in oldSet, sequentially and in the order of the skip sets. A nil callback ignores its kind of
+// change. If a callback returns false, the iteration stops.
+func DiffInt32(oldSet, newSet *Int32Set, onAdded, onRemoved func(value int32) bool) {
+	oldSet.diff(newSet, func(value int32, added bool) bool {
+		if added {
+			return onAdded == nil || onAdded(value)
+		}
+		return onRemoved == nil || onRemoved(value)
+	})
+}
+
+// DiffSeqInt32 is like DiffInt32 but returns the changes as an iterator of
+// (value, added) pairs, where added is false for the values removed from oldSet.
+func DiffSeqInt32(oldSet, newSet *Int32Set) func(yield func(value int32, added bool) bool) {
+	return func(yield func(value int32, added bool) bool) {
+		oldSet.diff(newSet, yield)
+	}
+}
+
+// diff calls yield for each value only in s (added is false) or only in other (added is true).
+func (s *Int32Set) diff(other *Int32Set, yield func(value int32, added bool) bool) {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil || y != nil {
+		switch {
+		case y == nil || x != nil && (x.value < y.value):
+			if !yield(x.value, false) {
+				return
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || (y.value < x.value):
+			if !yield(y.value, true) {
+				return
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+}
+
+// SplitAt moves all the values with `value >= k` (in the order of the skip set) into a new
+// skip set and returns it. The towers are cut at the split point in O(log n) time. The lengths
+// of the two parts are counted later, by the first call to Len or to a method modifying either
+// part, which walks the shorter part.
+//
+// SplitAt requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) SplitAt(k int32) *Int32Set {
+	s.countLength()
+	var preds, succs [maxLevel]*int32node
+	s.findNodeRemove(k, &preds, &succs)
+	right := s.newEmpty()
+	highestLevel := atomic.LoadUint64(&s.highestLevel)
+	for i := 0; i < int(highestLevel); i++ {
+		right.header.atomicStoreNext(i, succs[i])
+		preds[i].atomicStoreNext(i, nil)
+	}
+	atomic.StoreUint64(&right.highestLevel, highestLevel)
+
+	total := atomic.LoadInt64(&s.length)
+	c := &lengthCounter{count: func() {
+		// Count the shorter part by walking both parts in lockstep, neither part has been
+		// modified since the split.
+		var (
+			n    int64
+			l, r = s.header.loadNext(0), right.header.loadNext(0)
+		)
+		for l != nil && r != nil {
+			l, r = l.loadNext(0), r.loadNext(0)
+			n++
+		}
+		if r == nil {
+			atomic.StoreInt64(&right.length, n)
+			atomic.StoreInt64(&s.length, total-n)
+		} else {
+			atomic.StoreInt64(&right.length, total-n)
+			atomic.StoreInt64(&s.length, n)
+		}
+		atomic.StorePointer(&s.pending, nil)
+		atomic.StorePointer(&right.pending, nil)
+	}}
+	atomic.StorePointer(&s.pending, unsafe.Pointer(c))
+	atomic.StorePointer(&right.pending, unsafe.Pointer(c))
+	return right
+}
+
+// countLength counts the length of s if it was left pending by SplitAt, it must be called
+// before reading or modifying the length.
+func (s *Int32Set) countLength() {
+	if c := (*lengthCounter)(atomic.LoadPointer(&s.pending)); c != nil {
+		c.once.Do(c.count)
+	}
+}
+
+// Join moves all the values of right to the end of s in O(log n) time, leaving right empty.
+// The lengths left pending by SplitAt, if any, are counted first.
+// It returns false and does nothing if the first value of right is not greater than the last
+// value of s (in the order of the skip set).
+//
+// Join requires exclusive access, neither s nor right may be used by other goroutines during the call.
+func (s *Int32Set) Join(right *Int32Set) bool {
+	first := right.header.loadNext(0)
+	if first == nil {
+		return true
+	}
+	if right == s {
+		return false
+	}
+	s.countLength()
+	right.countLength()
+	// Find the last node in each level of s.
+	var (
+		tails [maxLevel]*int32node
+		x     = s.header
+	)
+	for i := maxLevel - 1; i >= 0; i-- {
+		for next := x.loadNext(i); next != nil; next = x.loadNext(i) {
+			x = next
+		}
+		tails[i] = x
+	}
+	if x != s.header && !(x.value < first.value) {
+		return false
+	}
+	rightLevel := atomic.LoadUint64(&right.highestLevel)
+	for i := 0; i < int(rightLevel); i++ {
+		tails[i].atomicStoreNext(i, right.header.loadNext(i))
+		right.header.atomicStoreNext(i, nil)
+	}
+	if rightLevel > atomic.LoadUint64(&s.highestLevel) {
+		atomic.StoreUint64(&s.highestLevel, rightLevel)
+	}
+	atomic.AddInt64(&s.length, atomic.SwapInt64(&right.length, 0))
+	return true
+}
+
+// MarshalJSON implements json.Marshaler. The skip set is encoded as a JSON array of its values
+// in the order of the skip set.
+func (s *Int32Set) MarshalJSON() ([]byte, error) {
+	if s.header == nil {
+		return []byte("[]"), nil
+	}
+	return marshalJSON(s.Range)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The values of the JSON array replace the values
+// of the skip set.
+//
+// UnmarshalJSON requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	values, err := unmarshalJSON[int32](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. The skip set is encoded as a slice of its values
+// in the order of the skip set.
+func (s *Int32Set) GobEncode() ([]byte, error) {
+	if s.header == nil {
+		return gobEncode[int32](nil)
+	}
+	return gobEncode(s.Range)
+}
+
+// GobDecode implements gob.GobDecoder. The decoded values replace the values of the skip set.
+//
+// GobDecode requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) GobDecode(data []byte) error {
+	values, err := gobDecode[int32](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The values are encoded in the order of
+// the skip set, as the first value followed by the varint distances between consecutive values.
+func (s *Int32Set) MarshalBinary() ([]byte, error) {
+	if s.header == nil {
+		return []byte{binaryVersion, 0}, nil
+	}
+	return marshalBinary(s.Range), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The decoded values replace the values
+// of the skip set, they are checked to be in the order of the skip set and appended in one pass.
+//
+// UnmarshalBinary requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) UnmarshalBinary(data []byte) error {
+	n := s.newEmpty()
+	if err := unmarshalBinary(data, false, n.newBuilder().append); err != nil {
+		return err
+	}
+	s.countLength()
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+	return nil
+}
+
+// WriteTo implements io.WriterTo, it writes the values to w like WriteToCodec with the default
+// codec of the value type: integers are encoded as varints, floats as their IEEE 754 bits,
+// strings as their length followed by their bytes. Other types require WriteToCodec.
+func (s *Int32Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := defaultCodec[int32]()
+	if err != nil {
+		return 0, err
+	}
+	return s.WriteToCodec(w, codec)
+}
+
+// WriteToCodec writes the values to w in the order of the skip set
+// as they are visited by Range.
+// The values are streamed without buffering the whole skip set, each one is encoded by codec
+// after a 1 byte, and a 0 byte marks the end of the stream.
+func (s *Int32Set) WriteToCodec(w io.Writer, codec Codec[int32]) (int64, error) {
+	if s.header == nil {
+		return writeTo(w, codec, nil)
+	}
+	return writeTo(w, codec, s.Range)
+}
+
+// ReadFrom implements io.ReaderFrom, it reads values from r like ReadFromCodec with the
+// default codec of the value type, see WriteTo.
+func (s *Int32Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := defaultCodec[int32]()
+	if err != nil {
+		return 0, err
+	}
+	return s.ReadFromCodec(r, codec)
+}
+
+// ReadFromCodec reads the values written by WriteToCodec from r and adds them into the skip set,
+// it returns the number of bytes read. It stops right after the end of the stream, so several
+// skip sets can be read back-to-back from r. Unless r implements io.ByteReader, it is read one
+// byte at a time, wrap it in a bufio.Reader for speed.
+// Each value is inserted by searching from the previous one, so reading values in the order of the
+// skip set takes linear time.
+//
+// ReadFromCodec requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) ReadFromCodec(r io.Reader, codec Codec[int32]) (int64, error) {
+	if s.header == nil {
+		n := s.newEmpty()
+		s.header, s.highestLevel = n.header, n.highestLevel
+	}
+	s.countLength()
+	b := s.newBuilder()
+	return readFrom(r, codec, func(value int32) {
+		b.insert(value)
+	})
+}
+
+// MarshalText implements encoding.TextMarshaler. The skip set is encoded as a comma-separated
+// list of its values in the order of the skip set.
+func (s *Int32Set) MarshalText() ([]byte, error) {
+	if s.header == nil {
+		return []byte{}, nil
+	}
+	return appendText(nil, s.Range, appendInteger[int32]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The values of the comma-separated list
+// replace the values of the skip set, see Set.
+//
+// UnmarshalText requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) UnmarshalText(text []byte) error {
+	values, err := parseText(string(text), parseInteger[int32])
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// Set implements flag.Value, it adds the values of a comma-separated list into the skip set,
+// so that a repeated flag accumulates its values. The spaces around the values are trimmed and
+// the empty values are skipped. No value is added if one of them is invalid.
+func (s *Int32Set) Set(value string) error {
+	values, err := parseText(value, parseInteger[int32])
+	if err != nil {
+		return err
+	}
+	if s.header == nil {
+		n := s.newEmpty()
+		s.header, s.highestLevel = n.header, n.highestLevel
+	}
+	for _, v := range values {
+		s.Add(v)
+	}
+	return nil
+}
+
+// String implements flag.Value, it returns the values as a comma-separated list in the order of
+// the skip set, so that flag can print the default values in the syntax accepted by Set.
+// Use Format for a bounded output, e.g. with %v.
+func (s *Int32Set) String() string {
+	if s.header == nil {
+		return ""
+	}
+	return string(appendText(nil, s.Range, appendInteger[int32]))
+}
+
+// Value implements driver.Valuer. The skip set is encoded as a PostgreSQL array literal of its
+// values in the order of the skip set, e.g. {1,2,3}.
+func (s *Int32Set) Value() (driver.Value, error) {
+	var rangeFn func(f func(value int32) bool)
+	if s.header != nil {
+		rangeFn = s.Range
+	}
+	return string(appendArray(nil, rangeFn, appendInteger[int32], false)), nil
+}
+
+// Scan implements sql.Scanner. The values of a PostgreSQL array literal, scanned from a string
+// or a []byte, replace the values of the skip set. A NULL column empties the skip set.
+//
+// Scan requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int32Set) Scan(src any) error {
+	values, err := scanArray(src, parseInteger[int32])
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// Format implements fmt.Formatter. The values are formatted with the verb in the order of the
+// skip set, e.g. {1, 2, 3}.
+// At most 16 values are shown, the precision sets another maximum, e.g. %.100v.
+// The plus flag adds the length, e.g. len=3 {1, 2, 3}.
+func (s *Int32Set) Format(f fmt.State, verb rune) {
+	if s.header == nil {
+		formatSet(f, verb, 0, nil)
+		return
+	}
+	formatSet(f, verb, s.Len(), func(emit func(value any, count int) bool) {
+		s.Range(func(value int32) bool {
+			return emit(value, -1)
+		})
+	})
+}
@@ -3,6 +3,11 @@
 package skipset
 
 import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -13,6 +18,8 @@ type Int64Set struct {
 	length       int64
 	highestLevel uint64 // highest level for now
 	header       *int64node
+	notify       unsafe.Pointer // *notifier, stored by the first follower
+	pending      unsafe.Pointer // *lengthCounter, stored by SplitAt until the length is counted
 }
 
 type int64node struct {
@@ -50,6 +57,15 @@ func (n *int64node) atomicStoreNext(i int, next *int64node) {
 	n.next.atomicStore(i, unsafe.Pointer(next))
 }
 
+// atomicLoadNextValid returns the first fully linked and unmarked node after n in level 0.
+func (n *int64node) atomicLoadNextValid() *int64node {
+	x := n.atomicLoadNext(0)
+	for x != nil && !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+
 // findNodeRemove takes a value and two maximal-height arrays then searches exactly as in a sequential skip-list.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
 func (s *Int64Set) findNodeRemove(value int64, preds *[maxLevel]*int64node, succs *[maxLevel]*int64node) int {
@@ -93,6 +109,119 @@ func (s *Int64Set) findNodeAdd(value int64, preds *[maxLevel]*int64node, succs *
 	return -1
 }
 
+// newEmpty returns an empty skip set in the same order as s.
+func (s *Int64Set) newEmpty() *Int64Set {
+	return NewInt64()
+}
+
+// int64builder appends values to a skip set in linear time.
+// The skip set must not be accessed by other goroutines until the build is done.
+type int64builder struct {
+	s    *Int64Set
+	tail [maxLevel]*int64node // the last node before or at the last appended value in each level
+}
+
+func (s *Int64Set) newBuilder() *int64builder {
+	b := &int64builder{s: s}
+	for i := range b.tail {
+		b.tail[i] = s.header
+	}
+	return b
+}
+
+// append adds the value right after the last appended value, the value must be greater than
+// it and less than the next value in the skip set (in the order of the skip set).
+func (b *int64builder) append(value int64) {
+	level := b.s.randomlevel()
+	nn := newInt64Node(value, level)
+	for i := 0; i < level; i++ {
+		nn.storeNext(i, b.tail[i].loadNext(i))
+		b.tail[i].storeNext(i, nn)
+		b.tail[i] = nn
+	}
+	nn.flags.SetTrue(fullyLinked)
+	b.s.length++
+}
+
+// insert adds the value wherever it belongs in the skip set, it returns false if the value is
+// already in the skip set.
+// The search starts from the last appended value, so inserting values in the order of the skip set
+// takes linear time.
+func (b *int64builder) insert(value int64) bool {
+	s, last := b.s, b.tail[0]
+	if next := last.loadNext(0); (last != s.header && !(last.value < value)) || (next != nil && !(value < next.value)) {
+		// The value doesn't follow the last appended value, search it from the header.
+		for i := range b.tail {
+			b.tail[i] = s.header
+		}
+		var succs [maxLevel]*int64node
+		if lFound := s.findNodeAdd(value, &b.tail, &succs); lFound != -1 {
+			// The search stopped at level lFound, b.tail is only valid for the header.
+			for i := range b.tail {
+				b.tail[i] = s.header
+			}
+			return false
+		}
+	}
+	b.append(value)
+	return true
+}
+
+// reset replaces the values of s with the given ones, which are sorted in place then appended in linear time,
+// repeated values are added once.
+// It requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) reset(values []int64) {
+	s.countLength()
+	sort.Slice(values, func(i, j int) bool {
+		return (values[i] < values[j])
+	})
+	n := s.newEmpty()
+	b := n.newBuilder()
+	for i, v := range values {
+		if i > 0 && v == values[i-1] {
+			continue
+		}
+		b.append(v)
+	}
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+}
+
+// NewInt64FromSorted returns a skip set with the given values, which must be in the order of
+// the skip set. Duplicate values are added once.
+// The skip list is built in linear time, with the same random levels as if the values were added
+// one by one. It returns ErrNotSorted if a value is out of order.
+func NewInt64FromSorted(values []int64) (*Int64Set, error) {
+	s := NewInt64()
+	b := s.newBuilder()
+	for i, v := range values {
+		if i > 0 {
+			if v < values[i-1] {
+				return nil, ErrNotSorted
+			}
+			if !(values[i-1] < v) {
+				continue
+			}
+		}
+		b.append(v)
+	}
+	return s, nil
+}
+
+// OfInt64 returns a skip set with the given values in any order, see FromSliceInt64.
+func OfInt64(values ...int64) *Int64Set {
+	return FromSliceInt64(values)
+}
+
+// FromSliceInt64 returns a skip set with the given values in any order,
+// duplicate values are added once.
+// A copy of the values is sorted in the order of the skip set, then the skip list is built
+// in linear time.
+func FromSliceInt64(values []int64) *Int64Set {
+	s := NewInt64()
+	s.reset(append([]int64(nil), values...))
+	return s
+}
+
 func unlockint64(preds [maxLevel]*int64node, highestLevel int) {
 	var prevPred *int64node
 	for i := highestLevel; i >= 0; i-- {
@@ -108,6 +237,7 @@ func unlockint64(preds [maxLevel]*int64node, highestLevel int) {
 //
 // If the value is in the skip set but not fully linked, this process will wait until it is.
 func (s *Int64Set) Add(value int64) bool {
+	s.countLength()
 	level := s.randomlevel()
 	var preds, succs [maxLevel]*int64node
 	for {
@@ -157,6 +287,9 @@ func (s *Int64Set) Add(value int64) bool {
 		nn.flags.SetTrue(fullyLinked)
 		unlockint64(preds, highestLocked)
 		atomic.AddInt64(&s.length, 1)
+		if n := loadNotifier(&s.notify); n != nil {
+			n.broadcast()
+		}
 		return true
 	}
 }
@@ -197,6 +330,7 @@ func (s *Int64Set) Contains(value int64) bool {
 
 // Remove removes a node from the skip set.
 func (s *Int64Set) Remove(value int64) bool {
+	s.countLength()
 	var (
 		nodeToRemove *int64node
 		isMarked     bool // represents if this operation mark the node
@@ -307,5 +441,666 @@ func (s *Int64Set) RangeFrom(start int64, f func(value int64) bool) {
 
 // Len returns the length of this skip set.
 func (s *Int64Set) Len() int {
+	s.countLength()
 	return int(atomic.LoadInt64(&s.length))
 }
+
+// last returns the last value in the skip set, it returns false if the skip set is empty.
+func (s *Int64Set) last() (int64, bool) {
+	// Only step onto valid nodes in the upper levels, so that x is never after the last valid node.
+	x := s.header
+	for i := int(atomic.LoadUint64(&s.highestLevel)) - 1; i > 0; i-- {
+		for next := x.atomicLoadNext(i); next != nil && next.flags.MGet(fullyLinked|marked, fullyLinked); next = x.atomicLoadNext(i) {
+			x = next
+		}
+	}
+	last := x
+	for x = x.atomicLoadNextValid(); x != nil; x = x.atomicLoadNextValid() {
+		last = x
+	}
+	if last == s.header {
+		var zero int64
+		return zero, false
+	}
+	return last.value, true
+}
+
+// Follow calls f sequentially for all values with `value >= start` in the skip set, like RangeFrom,
+// then waits for values greater than the last value passed to f and calls f for them as they are
+// added, like `tail -f`. Values added behind the last value passed to f are skipped.
+//
+// Follow returns nil once f returns false, or ctx.Err() once ctx is done. It does not spin while
+// waiting: Add wakes up the followers after inserting a value.
+func (s *Int64Set) Follow(ctx context.Context, start int64, f func(value int64) bool) error {
+	var (
+		n       = loadOrStoreNotifier(&s.notify)
+		cursor  = start
+		started bool // cursor has been passed to f
+		stopped bool
+	)
+	next := func(value int64) bool {
+		if started && !(cursor < value) {
+			return true
+		}
+		cursor, started = value, true
+		if !f(value) {
+			stopped = true
+			return false
+		}
+		return true
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Get the channel before scanning, so that a value added after the scan
+		// always closes it.
+		ch := n.wait()
+		s.RangeFrom(cursor, next)
+		if stopped {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Quantile returns the q-quantile (0 <= q <= 1) of the skip set, that is the element whose
+// rank in the skip set is ceil(q*n) for n elements. Quantile(0) and Quantile(1) are always
+// exactly the first and the last element. It returns false if the skip set is empty or q is
+// out of range.
+//
+// The skip set does not maintain span counts, so only sets with fewer than 4096 elements
+// are walked at level 0 and get an exact answer for every q. Larger sets are estimated from the highest
+// level expected to hold at least 1024 nodes: every element reaches that level independently
+// with the same probability, so the sampled quantile is off by at most about
+// sqrt(q*(1-q)/1024) (1.6% of the rank range for the median) in one standard deviation.
+func (s *Int64Set) Quantile(q float64) (int64, bool) {
+	res, ok := s.Quantiles(q)
+	if !ok {
+		var zero int64
+		return zero, false
+	}
+	return res[0], true
+}
+
+// Quantiles is like Quantile but estimates several quantiles with a single scan.
+// The results are in the same order as qs. It returns false if the skip set is empty
+// or any q is out of range.
+func (s *Int64Set) Quantiles(qs ...float64) ([]int64, bool) {
+	level := quantileLevel(s.Len(), int(atomic.LoadUint64(&s.highestLevel)))
+	var count int
+	for x := s.header.atomicLoadNext(level); x != nil; x = x.atomicLoadNext(level) {
+		if x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			count++
+		}
+	}
+	ranks, order, ok := quantileRanks(qs, count)
+	if !ok || count == 0 {
+		return nil, false
+	}
+	var (
+		res   = make([]int64, len(qs))
+		i     int
+		rank  int
+		last  int64
+		found bool
+	)
+	for x := s.header.atomicLoadNext(level); x != nil && i < len(order); x = x.atomicLoadNext(level) {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			continue
+		}
+		for i < len(order) && ranks[order[i]] == rank {
+			res[order[i]] = x.value
+			i++
+		}
+		last, found = x.value, true
+		rank++
+	}
+	if i < len(order) {
+		// Some elements have been removed since counting.
+		if !found {
+			return nil, false
+		}
+		for ; i < len(order); i++ {
+			res[order[i]] = last
+		}
+	}
+	if level != 0 {
+		// The endpoints are cheap to find exactly: the first node of level 0 and the tail.
+		for i, q := range qs {
+			switch q {
+			case 0:
+				if x := s.header.atomicLoadNextValid(); x != nil {
+					res[i] = x.value
+				}
+			case 1:
+				if v, ok := s.last(); ok {
+					res[i] = v
+				}
+			}
+		}
+	}
+	return res, true
+}
+
+// MergeInt64 returns a function that calls f sequentially for each value present in
+// any of the skip sets, in the order of the skip sets. Values present in several skip sets are
+// passed to f only once. If f returns false, the iteration stops.
+//
+// The level-0 lists of the skip sets are walked in lockstep without copying any data, so the
+// iteration observes concurrent modifications the same way Range does.
+func MergeInt64(sets ...*Int64Set) func(f func(value int64) bool) {
+	return func(f func(value int64) bool) {
+		if len(sets) != 0 {
+			sets[0].rangeMerge(sets, f)
+		}
+	}
+}
+
+// rangeMerge calls f for each distinct value of sets, using the ordering of s.
+func (s *Int64Set) rangeMerge(sets []*Int64Set, f func(value int64) bool) {
+	h := mergeHeap[*int64node]{
+		nodes: make([]*int64node, 0, len(sets)),
+		less: func(a, b *int64node) bool {
+			return (a.value < b.value)
+		},
+	}
+	for _, set := range sets {
+		if x := set.header.atomicLoadNextValid(); x != nil {
+			h.push(x)
+		}
+	}
+	var (
+		last    int64
+		emitted bool
+	)
+	for len(h.nodes) != 0 {
+		x := h.nodes[0]
+		if !emitted || (last < x.value) {
+			if !f(x.value) {
+				return
+			}
+			last, emitted = x.value, true
+		}
+		if next := x.atomicLoadNextValid(); next != nil {
+			h.nodes[0] = next
+			h.fix()
+		} else {
+			h.pop()
+		}
+	}
+}
+
+// UnionInt64 returns a new skip set with the values present in a or b.
+//
+// UnionInt64, IntersectInt64, DifferenceInt64 and SymmetricDifferenceInt64 merge-walk
+// the level-0 lists of both skip sets and build the result in linear time. The skip sets
+// may be modified concurrently, in which case the result observes the modifications the
+// same way Range does.
+func UnionInt64(a, b *Int64Set) *Int64Set {
+	return a.combine(b, true, true, true)
+}
+
+// IntersectInt64 returns a new skip set with the values present in both a and b.
+func IntersectInt64(a, b *Int64Set) *Int64Set {
+	return a.combine(b, false, true, false)
+}
+
+// DifferenceInt64 returns a new skip set with the values present in a but not in b.
+func DifferenceInt64(a, b *Int64Set) *Int64Set {
+	return a.combine(b, true, false, false)
+}
+
+// SymmetricDifferenceInt64 returns a new skip set with the values present in exactly one of a and b.
+func SymmetricDifferenceInt64(a, b *Int64Set) *Int64Set {
+	return a.combine(b, true, false, true)
+}
+
+// combine merge-walks s and other, the result contains the values only in s if onlyS is true,
+// the values in both if both is true, and the values only in other if onlyOther is true.
+func (s *Int64Set) combine(other *Int64Set, onlyS, both, onlyOther bool) *Int64Set {
+	res := s.newEmpty()
+	b := res.newBuilder()
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for (x != nil || onlyOther) && (y != nil || onlyS) && (x != nil || y != nil) {
+		switch {
+		case y == nil || x != nil && (x.value < y.value):
+			if onlyS {
+				b.append(x.value)
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || (y.value < x.value):
+			if onlyOther {
+				b.append(y.value)
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			if both {
+				b.append(x.value)
+			}
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return res
+}
+
+// EqualInt64 reports whether a and b contain the same values.
+//
+// EqualInt64, IsSubsetInt64, IsSupersetInt64 and DisjointInt64 merge-walk the level-0 lists
+// of both skip sets and return as soon as the answer is known. They compare the lengths first
+// when it can decide the answer, so if the skip sets are modified concurrently the result may
+// reflect any state of them during the call.
+func EqualInt64(a, b *Int64Set) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	return a.isSubset(b, true)
+}
+
+// IsSubsetInt64 reports whether every value of a is in b.
+func IsSubsetInt64(a, b *Int64Set) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	return a.isSubset(b, false)
+}
+
+// IsSupersetInt64 reports whether every value of b is in a.
+func IsSupersetInt64(a, b *Int64Set) bool {
+	return IsSubsetInt64(b, a)
+}
+
+// DisjointInt64 reports whether a and b have no value in common.
+func DisjointInt64(a, b *Int64Set) bool {
+	if a.Len() == 0 || b.Len() == 0 {
+		return true
+	}
+	return a.disjoint(b)
+}
+
+// disjoint reports whether s and other have no value in common.
+func (s *Int64Set) disjoint(other *Int64Set) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil && y != nil {
+		switch {
+		case (x.value < y.value):
+			x = x.atomicLoadNextValid()
+		case (y.value < x.value):
+			y = y.atomicLoadNextValid()
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isSubset reports whether every value of s is in other, and if equal is true,
+// whether every value of other is in s as well.
+func (s *Int64Set) isSubset(other *Int64Set, equal bool) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil {
+		switch {
+		case y == nil || (x.value < y.value):
+			return false // x is not in other
+		case (y.value < x.value):
+			if equal {
+				return false // y is not in s
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return !equal || y == nil
+}
+
+// DiffInt64 reports the changes from oldSet to newSet in a single merge-walk of both
+// skip sets: onAdded is called for each value only in newSet and onRemoved for each value only
+// in oldSet, sequentially and in the order of the skip sets. A nil callback ignores its kind of
+// change. If a callback returns false, the iteration stops.
+func DiffInt64(oldSet, newSet *Int64Set, onAdded, onRemoved func(value int64) bool) {
+	oldSet.diff(newSet, func(value int64, added bool) bool {
+		if added {
+			return onAdded == nil || onAdded(value)
+		}
+		return onRemoved == nil || onRemoved(value)
+	})
+}
+
+// DiffSeqInt64 is like DiffInt64 but returns the changes as an iterator of
+// (value, added) pairs, where added is false for the values removed from oldSet.
+func DiffSeqInt64(oldSet, newSet *Int64Set) func(yield func(value int64, added bool) bool) {
+	return func(yield func(value int64, added bool) bool) {
+		oldSet.diff(newSet, yield)
+	}
+}
+
+// diff calls yield for each value only in s (added is false) or only in other (added is true).
+func (s *Int64Set) diff(other *Int64Set, yield func(value int64, added bool) bool) {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil || y != nil {
+		switch {
+		case y == nil || x != nil && (x.value < y.value):
+			if !yield(x.value, false) {
+				return
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || (y.value < x.value):
+			if !yield(y.value, true) {
+				return
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+}
+
+// SplitAt moves all the values with `value >= k` (in the order of the skip set) into a new
+// skip set and returns it. The towers are cut at the split point in O(log n) time. The lengths
+// of the two parts are counted later, by the first call to Len or to a method modifying either
+// part, which walks the shorter part.
+//
+// SplitAt requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) SplitAt(k int64) *Int64Set {
+	s.countLength()
+	var preds, succs [maxLevel]*int64node
+	s.findNodeRemove(k, &preds, &succs)
+	right := s.newEmpty()
+	highestLevel := atomic.LoadUint64(&s.highestLevel)
+	for i := 0; i < int(highestLevel); i++ {
+		right.header.atomicStoreNext(i, succs[i])
+		preds[i].atomicStoreNext(i, nil)
+	}
+	atomic.StoreUint64(&right.highestLevel, highestLevel)
+
+	total := atomic.LoadInt64(&s.length)
+	c := &lengthCounter{count: func() {
+		// Count the shorter part by walking both parts in lockstep, neither part has been
+		// modified since the split.
+		var (
+			n    int64
+			l, r = s.header.loadNext(0), right.header.loadNext(0)
+		)
+		for l != nil && r != nil {
+			l, r = l.loadNext(0), r.loadNext(0)
+			n++
+		}
+		if r == nil {
+			atomic.StoreInt64(&right.length, n)
+			atomic.StoreInt64(&s.length, total-n)
+		} else {
+			atomic.StoreInt64(&right.length, total-n)
+			atomic.StoreInt64(&s.length, n)
+		}
+		atomic.StorePointer(&s.pending, nil)
+		atomic.StorePointer(&right.pending, nil)
+	}}
+	atomic.StorePointer(&s.pending, unsafe.Pointer(c))
+	atomic.StorePointer(&right.pending, unsafe.Pointer(c))
+	return right
+}
+
+// countLength counts the length of s if it was left pending by SplitAt, it must be called
+// before reading or modifying the length.
+func (s *Int64Set) countLength() {
+	if c := (*lengthCounter)(atomic.LoadPointer(&s.pending)); c != nil {
+		c.once.Do(c.count)
+	}
+}
+
+// Join moves all the values of right to the end of s in O(log n) time, leaving right empty.
+// The lengths left pending by SplitAt, if any, are counted first.
+// It returns false and does nothing if the first value of right is not greater than the last
+// value of s (in the order of the skip set).
+//
+// Join requires exclusive access, neither s nor right may be used by other goroutines during the call.
+func (s *Int64Set) Join(right *Int64Set) bool {
+	first := right.header.loadNext(0)
+	if first == nil {
+		return true
+	}
+	if right == s {
+		return false
+	}
+	s.countLength()
+	right.countLength()
+	// Find the last node in each level of s.
+	var (
+		tails [maxLevel]*int64node
+		x     = s.header
+	)
+	for i := maxLevel - 1; i >= 0; i-- {
+		for next := x.loadNext(i); next != nil; next = x.loadNext(i) {
+			x = next
+		}
+		tails[i] = x
+	}
+	if x != s.header && !(x.value < first.value) {
+		return false
+	}
+	rightLevel := atomic.LoadUint64(&right.highestLevel)
+	for i := 0; i < int(rightLevel); i++ {
+		tails[i].atomicStoreNext(i, right.header.loadNext(i))
+		right.header.atomicStoreNext(i, nil)
+	}
+	if rightLevel > atomic.LoadUint64(&s.highestLevel) {
+		atomic.StoreUint64(&s.highestLevel, rightLevel)
+	}
+	atomic.AddInt64(&s.length, atomic.SwapInt64(&right.length, 0))
+	return true
+}
+
+// MarshalJSON implements json.Marshaler. The skip set is encoded as a JSON array of its values
+// in the order of the skip set.
+func (s *Int64Set) MarshalJSON() ([]byte, error) {
+	if s.header == nil {
+		return []byte("[]"), nil
+	}
+	return marshalJSON(s.Range)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The values of the JSON array replace the values
+// of the skip set.
+//
+// UnmarshalJSON requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	values, err := unmarshalJSON[int64](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. The skip set is encoded as a slice of its values
+// in the order of the skip set.
+func (s *Int64Set) GobEncode() ([]byte, error) {
+	if s.header == nil {
+		return gobEncode[int64](nil)
+	}
+	return gobEncode(s.Range)
+}
+
+// GobDecode implements gob.GobDecoder. The decoded values replace the values of the skip set.
+//
+// GobDecode requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) GobDecode(data []byte) error {
+	values, err := gobDecode[int64](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The values are encoded in the order of
+// the skip set, as the first value followed by the varint distances between consecutive values.
+func (s *Int64Set) MarshalBinary() ([]byte, error) {
+	if s.header == nil {
+		return []byte{binaryVersion, 0}, nil
+	}
+	return marshalBinary(s.Range), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The decoded values replace the values
+// of the skip set, they are checked to be in the order of the skip set and appended in one pass.
+//
+// UnmarshalBinary requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) UnmarshalBinary(data []byte) error {
+	n := s.newEmpty()
+	if err := unmarshalBinary(data, false, n.newBuilder().append); err != nil {
+		return err
+	}
+	s.countLength()
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+	return nil
+}
+
+// WriteTo implements io.WriterTo, it writes the values to w like WriteToCodec with the default
+// codec of the value type: integers are encoded as varints, floats as their IEEE 754 bits,
+// strings as their length followed by their bytes. Other types require WriteToCodec.
+func (s *Int64Set) WriteTo(w io.Writer) (int64, error) {
+	codec, err := defaultCodec[int64]()
+	if err != nil {
+		return 0, err
+	}
+	return s.WriteToCodec(w, codec)
+}
+
+// WriteToCodec writes the values to w in the order of the skip set
+// as they are visited by Range.
+// The values are streamed without buffering the whole skip set, each one is encoded by codec
+// after a 1 byte, and a 0 byte marks the end of the stream.
+func (s *Int64Set) WriteToCodec(w io.Writer, codec Codec[int64]) (int64, error) {
+	if s.header == nil {
+		return writeTo(w, codec, nil)
+	}
+	return writeTo(w, codec, s.Range)
+}
+
+// ReadFrom implements io.ReaderFrom, it reads values from r like ReadFromCodec with the
+// default codec of the value type, see WriteTo.
+func (s *Int64Set) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := defaultCodec[int64]()
+	if err != nil {
+		return 0, err
+	}
+	return s.ReadFromCodec(r, codec)
+}
+
+// ReadFromCodec reads the values written by WriteToCodec from r and adds them into the skip set,
+// it returns the number of bytes read. It stops right after the end of the stream, so several
+// skip sets can be read back-to-back from r. Unless r implements io.ByteReader, it is read one
+// byte at a time, wrap it in a bufio.Reader for speed.
+// Each value is inserted by searching from the previous one, so reading values in the order of the
+// skip set takes linear time.
+//
+// ReadFromCodec requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) ReadFromCodec(r io.Reader, codec Codec[int64]) (int64, error) {
+	if s.header == nil {
+		n := s.newEmpty()
+		s.header, s.highestLevel = n.header, n.highestLevel
+	}
+	s.countLength()
+	b := s.newBuilder()
+	return readFrom(r, codec, func(value int64) {
+		b.insert(value)
+	})
+}
+
+// MarshalText implements encoding.TextMarshaler. The skip set is encoded as a comma-separated
+// list of its values in the order of the skip set.
+func (s *Int64Set) MarshalText() ([]byte, error) {
+	if s.header == nil {
+		return []byte{}, nil
+	}
+	return appendText(nil, s.Range, appendInteger[int64]), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The values of the comma-separated list
+// replace the values of the skip set, see Set.
+//
+// UnmarshalText requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) UnmarshalText(text []byte) error {
+	values, err := parseText(string(text), parseInteger[int64])
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// Set implements flag.Value, it adds the values of a comma-separated list into the skip set,
+// so that a repeated flag accumulates its values. The spaces around the values are trimmed and
+// the empty values are skipped. No value is added if one of them is invalid.
+func (s *Int64Set) Set(value string) error {
+	values, err := parseText(value, parseInteger[int64])
+	if err != nil {
+		return err
+	}
+	if s.header == nil {
+		n := s.newEmpty()
+		s.header, s.highestLevel = n.header, n.highestLevel
+	}
+	for _, v := range values {
+		s.Add(v)
+	}
+	return nil
+}
+
+// String implements flag.Value, it returns the values as a comma-separated list in the order of
+// the skip set, so that flag can print the default values in the syntax accepted by Set.
+// Use Format for a bounded output, e.g. with %v.
+func (s *Int64Set) String() string {
+	if s.header == nil {
+		return ""
+	}
+	return string(appendText(nil, s.Range, appendInteger[int64]))
+}
+
+// Value implements driver.Valuer. The skip set is encoded as a PostgreSQL array literal of its
+// values in the order of the skip set, e.g. {1,2,3}.
+func (s *Int64Set) Value() (driver.Value, error) {
+	var rangeFn func(f func(value int64) bool)
+	if s.header != nil {
+		rangeFn = s.Range
+	}
+	return string(appendArray(nil, rangeFn, appendInteger[int64], false)), nil
+}
+
+// Scan implements sql.Scanner. The values of a PostgreSQL array literal, scanned from a string
+// or a []byte, replace the values of the skip set. A NULL column empties the skip set.
+//
+// Scan requires exclusive access, s must not be used by other goroutines during the call.
+func (s *Int64Set) Scan(src any) error {
+	values, err := scanArray(src, parseInteger[int64])
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// Format implements fmt.Formatter. The values are formatted with the verb in the order of the
+// skip set, e.g. {1, 2, 3}.
+// At most 16 values are shown, the precision sets another maximum, e.g. %.100v.
+// The plus flag adds the length, e.g. len=3 {1, 2, 3}.
+func (s *Int64Set) Format(f fmt.State, verb rune) {
+	if s.header == nil {
+		formatSet(f, verb, 0, nil)
+		return
+	}
+	formatSet(f, verb, s.Len(), func(emit func(value any, count int) bool) {
+		s.Range(func(value int64) bool {
+			return emit(value, -1)
+		})
+	})
+}
@@ -0,0 +1,32 @@
+package skipset
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// gobEncode encodes the values passed to f by rangeFn as a gob slice,
+// rangeFn may be nil if there is no value.
+func gobEncode[T any](rangeFn func(f func(value T) bool)) ([]byte, error) {
+	values := []T{}
+	if rangeFn != nil {
+		rangeFn(func(value T) bool {
+			values = append(values, value)
+			return true
+		})
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(values); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gobDecode decodes the values encoded by gobEncode.
+func gobDecode[T any](data []byte) ([]T, error) {
+	var values []T
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
@@ -0,0 +1,63 @@
+package skipset
+
+import (
+	"bytes"
+	"encoding/gob"
+	"math"
+	"testing"
+)
+
+func TestGob(t *testing.T) {
+	type message struct {
+		A *OrderedSet[int]
+		B *StringSetDesc
+		C *FuncSet[float64]
+		D *MultiSet[int]
+		E *Uint32Set
+	}
+	in := message{
+		A: New[int](),
+		B: NewStringDesc(),
+		C: NewFloat64(),
+		D: NewMulti[int](),
+		E: NewUint32(),
+	}
+	for i := 0; i < 3; i++ {
+		in.A.Add(i)
+		in.B.Add(string(rune('a' + i)))
+		in.C.Add(float64(i))
+		in.D.Add(i)
+		in.D.Add(1)
+	}
+	in.C.Add(math.NaN())
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	// The less function of a FuncSet is supplied by creating it before decoding.
+	out := message{C: NewFloat64()}
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int](t, out.A, []int{0, 1, 2})
+	checkSet[string](t, out.B, []string{"c", "b", "a"})
+	checkMultiSet(t, out.D.Range, []int{0, 1, 2}, []int{1, 4, 1})
+	checkSet[uint32](t, out.E, nil)
+	var got []float64
+	out.C.Range(func(v float64) bool {
+		got = append(got, v)
+		return true
+	})
+	if len(got) != 4 || !math.IsNaN(got[0]) || got[1] != 0 || got[3] != 2 {
+		t.Fatal("invalid FuncSet", got)
+	}
+	out.A.Add(-1) // the decoded skip set is usable
+	checkSet[int](t, out.A, []int{-1, 0, 1, 2})
+
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&message{}); err != errNoLess {
+		t.Fatal("invalid error", err)
+	}
+}
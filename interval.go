@@ -0,0 +1,195 @@
+package skipset
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// IntervalSet represents a set of integers based on skip list. The integers are stored as
+// coalesced closed intervals keyed by their start, so long runs cost a single node.
+//
+// Contains, Covers and Range are wait-free like the ones of the skip sets. AddRange and
+// RemoveRange are serialized, and they update the skip list in an order such that a reader
+// never misses an integer which is in the set both before and after the update.
+type IntervalSet[T integer] struct {
+	mu   sync.Mutex // serializes AddRange and RemoveRange
+	list *FuncSet[*interval[T]]
+}
+
+type interval[T integer] struct {
+	lo T
+	hi unsafe.Pointer // *T, the end can be extended or shrunk in place
+}
+
+func newInterval[T integer](lo, hi T) *interval[T] {
+	return &interval[T]{lo: lo, hi: unsafe.Pointer(&hi)}
+}
+
+func (v *interval[T]) loadHi() T {
+	return *(*T)(atomic.LoadPointer(&v.hi))
+}
+
+func (v *interval[T]) storeHi(hi T) {
+	atomic.StorePointer(&v.hi, unsafe.Pointer(&hi))
+}
+
+// NewInterval returns an empty interval set.
+func NewInterval[T integer]() *IntervalSet[T] {
+	return &IntervalSet[T]{
+		list: NewFunc(func(a, b *interval[T]) bool {
+			return a.lo < b.lo
+		}),
+	}
+}
+
+// floor returns the interval with the greatest start <= x, or nil if there is none.
+func (s *IntervalSet[T]) floor(x T) *interval[T] {
+	for {
+		n := s.list.header
+		for i := int(atomic.LoadUint64(&s.list.highestLevel)) - 1; i >= 0; i-- {
+			for next := n.atomicLoadNext(i); next != nil && next.value.lo <= x; next = n.atomicLoadNext(i) {
+				n = next
+			}
+		}
+		if n == s.list.header {
+			return nil
+		}
+		if n.flags.MGet(fullyLinked|marked, fullyLinked) {
+			return n.value
+		}
+		// The interval is being added or removed, the previous one is still valid.
+		if x = n.value.lo - 1; x > n.value.lo {
+			return nil // overflow
+		}
+	}
+}
+
+// find returns the interval with the greatest start <= x and its end, or nil if there is none.
+func (s *IntervalSet[T]) find(x T) (*interval[T], T) {
+	f := s.floor(x)
+	for f != nil {
+		hi := f.loadHi()
+		// RemoveRange only shrinks an interval after adding its remaining part as a new one,
+		// so f is still valid if it is still the floor of x.
+		g := s.floor(x)
+		if g == f {
+			return f, hi
+		}
+		f = g
+	}
+	return nil, 0
+}
+
+// AddRange adds all the integers in [lo, hi] into the set, merging the overlapping
+// and adjacent intervals. It does nothing if lo > hi.
+func (s *IntervalSet[T]) AddRange(lo, hi T) {
+	if lo > hi {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Extend [lo, hi] with the interval before it and all the intervals starting in it.
+	f := s.floor(lo)
+	if f != nil && (f.loadHi() >= lo || f.loadHi()+1 == lo) {
+		lo = f.lo
+		if fhi := f.loadHi(); fhi > hi {
+			hi = fhi
+		}
+	} else {
+		f = nil
+	}
+	var absorbed []*interval[T]
+	s.list.RangeFrom(&interval[T]{lo: lo}, func(v *interval[T]) bool {
+		if v == f {
+			return true
+		}
+		if v.lo > hi && v.lo-1 != hi {
+			return false
+		}
+		absorbed = append(absorbed, v)
+		if vhi := v.loadHi(); vhi > hi {
+			hi = vhi
+		}
+		return true
+	})
+
+	// Make the new interval visible before removing the absorbed ones.
+	if f != nil {
+		f.storeHi(hi)
+	} else {
+		s.list.Add(newInterval(lo, hi))
+	}
+	for _, v := range absorbed {
+		s.list.Remove(v)
+	}
+}
+
+// RemoveRange removes all the integers in [lo, hi] from the set, splitting the interval
+// containing them if needed. It does nothing if lo > hi.
+func (s *IntervalSet[T]) RemoveRange(lo, hi T) {
+	if lo > hi {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var overlapping []*interval[T]
+	if f := s.floor(lo); f != nil && f.loadHi() >= lo {
+		overlapping = append(overlapping, f)
+	}
+	s.list.RangeFrom(&interval[T]{lo: lo}, func(v *interval[T]) bool {
+		if v.lo > hi {
+			return false
+		}
+		if len(overlapping) == 0 || overlapping[0] != v {
+			overlapping = append(overlapping, v)
+		}
+		return true
+	})
+	if len(overlapping) == 0 {
+		return
+	}
+
+	// Make the remaining parts visible before removing the overlapping intervals.
+	first, last := overlapping[0], overlapping[len(overlapping)-1]
+	if lasthi := last.loadHi(); lasthi > hi {
+		s.list.Add(newInterval(hi+1, lasthi))
+	}
+	if first.lo < lo {
+		first.storeHi(lo - 1)
+		overlapping = overlapping[1:]
+	}
+	for _, v := range overlapping {
+		s.list.Remove(v)
+	}
+}
+
+// Contains checks if x is in the set.
+func (s *IntervalSet[T]) Contains(x T) bool {
+	f, hi := s.find(x)
+	return f != nil && x <= hi
+}
+
+// Covers checks if all the integers in [lo, hi] are in the set. It returns true if lo > hi.
+func (s *IntervalSet[T]) Covers(lo, hi T) bool {
+	if lo > hi {
+		return true
+	}
+	f, fhi := s.find(lo)
+	return f != nil && hi <= fhi
+}
+
+// Range calls f sequentially for each coalesced interval [lo, hi] in the set, in ascending order.
+// If f returns false, range stops the iteration.
+func (s *IntervalSet[T]) Range(f func(lo, hi T) bool) {
+	s.list.Range(func(v *interval[T]) bool {
+		return f(v.lo, v.loadHi())
+	})
+}
+
+// Len returns the number of coalesced intervals in the set.
+func (s *IntervalSet[T]) Len() int {
+	return s.list.Len()
+}
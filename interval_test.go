@@ -0,0 +1,141 @@
+package skipset
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestIntervalSet(t *testing.T) {
+	s := NewInterval[int]()
+	s.AddRange(10, 20)
+	s.AddRange(30, 40)
+	s.AddRange(21, 25) // adjacent
+	s.AddRange(5, 3)   // empty
+	checkIntervals(t, s, [][2]int{{10, 25}, {30, 40}})
+	s.AddRange(24, 31)
+	checkIntervals(t, s, [][2]int{{10, 40}})
+	s.AddRange(0, 100)
+	checkIntervals(t, s, [][2]int{{0, 100}})
+	if !s.Contains(0) || !s.Contains(100) || s.Contains(101) || s.Contains(-1) {
+		t.Fatal("invalid contains")
+	}
+
+	s.RemoveRange(40, 60)
+	checkIntervals(t, s, [][2]int{{0, 39}, {61, 100}})
+	s.RemoveRange(-10, 5)
+	s.RemoveRange(95, 200)
+	checkIntervals(t, s, [][2]int{{6, 39}, {61, 94}})
+	s.RemoveRange(6, 6)
+	s.RemoveRange(70, 69) // empty
+	checkIntervals(t, s, [][2]int{{7, 39}, {61, 94}})
+	if !s.Covers(7, 39) || s.Covers(7, 40) || s.Covers(30, 70) || !s.Covers(80, 80) || !s.Covers(1, 0) {
+		t.Fatal("invalid covers")
+	}
+	s.RemoveRange(0, 100)
+	checkIntervals(t, s, nil)
+
+	// Boundaries of the integer type.
+	b := NewInterval[int8]()
+	b.AddRange(-128, -100)
+	b.AddRange(100, 127)
+	b.AddRange(-99, -99)
+	checkIntervals(t, b, [][2]int8{{-128, -99}, {100, 127}})
+	b.RemoveRange(-128, -128)
+	b.RemoveRange(127, 127)
+	checkIntervals(t, b, [][2]int8{{-127, -99}, {100, 126}})
+	if b.Contains(-128) || !b.Contains(-127) || b.Contains(127) {
+		t.Fatal("invalid contains")
+	}
+}
+
+func TestIntervalSetRandom(t *testing.T) {
+	s := NewInterval[uint8]()
+	var model [256]bool
+	for i := 0; i < 10000; i++ {
+		lo, hi := uint8(fastrand.Uint32n(256)), uint8(fastrand.Uint32n(256))
+		if lo > hi && fastrand.Uint32n(2) == 0 {
+			lo, hi = hi, lo
+		}
+		add := fastrand.Uint32n(2) == 0
+		if add {
+			s.AddRange(lo, hi)
+		} else {
+			s.RemoveRange(lo, hi)
+		}
+		for v := int(lo); v <= int(hi); v++ {
+			model[v] = add
+		}
+
+		// Check the coalesced intervals against the model.
+		var expected [][2]uint8
+		for v := 0; v < 256; v++ {
+			if !model[v] {
+				continue
+			}
+			if n := len(expected); n > 0 && int(expected[n-1][1]) == v-1 {
+				expected[n-1][1] = uint8(v)
+			} else {
+				expected = append(expected, [2]uint8{uint8(v), uint8(v)})
+			}
+		}
+		checkIntervals(t, s, expected)
+		v := uint8(fastrand.Uint32n(256))
+		if s.Contains(v) != model[v] {
+			t.Fatal("invalid contains", v)
+		}
+	}
+}
+
+func TestIntervalSetConcurrent(t *testing.T) {
+	s := NewInterval[int64]()
+	s.AddRange(1000, 2000)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				// Never touch [1400, 1600].
+				lo := int64(fastrand.Uint32n(3000))
+				hi := lo + int64(fastrand.Uint32n(100))
+				if hi >= 1400 && lo <= 1600 {
+					continue
+				}
+				if fastrand.Uint32n(2) == 0 {
+					s.AddRange(lo, hi)
+				} else {
+					s.RemoveRange(lo, hi)
+				}
+			}
+		}()
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			if !s.Contains(int64(1400+fastrand.Uint32n(201))) || !s.Covers(1400, 1600) {
+				t.Fatal("lost a stable range")
+			}
+		}
+	}
+}
+
+func checkIntervals[T integer](t *testing.T, s *IntervalSet[T], expected [][2]T) {
+	t.Helper()
+	var got [][2]T
+	s.Range(func(lo, hi T) bool {
+		got = append(got, [2]T{lo, hi})
+		return true
+	})
+	if !slicesEqual(got, expected) || s.Len() != len(expected) {
+		t.Fatalf("Expected: %v\n Got: %v (length %d)\n", expected, got, s.Len())
+	}
+}
@@ -0,0 +1,113 @@
+package skipset
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+)
+
+var errNoLess = errors.New("skipset: the set has no less function, create it with its constructor before decoding")
+
+// marshalJSON encodes the values passed to f by rangeFn as a JSON array.
+// NaN and ±Inf, which JSON can't represent, are encoded as the strings "NaN", "+Inf" and "-Inf".
+func marshalJSON[T any](rangeFn func(f func(value T) bool)) ([]byte, error) {
+	var (
+		buf = []byte{'['}
+		err error
+	)
+	rangeFn(func(value T) bool {
+		if len(buf) > 1 {
+			buf = append(buf, ',')
+		}
+		if name, ok := floatSpecial(value); ok {
+			buf = append(buf, '"')
+			buf = append(buf, name...)
+			buf = append(buf, '"')
+			return true
+		}
+		var b []byte
+		if b, err = json.Marshal(value); err != nil {
+			return false
+		}
+		buf = append(buf, b...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(buf, ']'), nil
+}
+
+// unmarshalJSON decodes a JSON array encoded by marshalJSON.
+func unmarshalJSON[T any](data []byte) ([]T, error) {
+	var values []T
+	switch any(values).(type) {
+	case []float32, []float64:
+		var raw []json.RawMessage
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return nil, err
+		}
+		values = make([]T, len(raw))
+		for i, r := range raw {
+			var name string
+			if json.Unmarshal(r, &name) == nil {
+				v, ok := floatFromSpecial[T](name)
+				if !ok {
+					return nil, errors.New("skipset: invalid float value " + string(r))
+				}
+				values[i] = v
+				continue
+			}
+			if err := json.Unmarshal(r, &values[i]); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		if err := json.Unmarshal(data, &values); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// floatSpecial returns the name of value if it is NaN or ±Inf.
+func floatSpecial[T any](value T) (string, bool) {
+	var f float64
+	switch v := any(value).(type) {
+	case float32:
+		f = float64(v)
+	case float64:
+		f = v
+	default:
+		return "", false
+	}
+	switch {
+	case f != f:
+		return "NaN", true
+	case math.IsInf(f, 1):
+		return "+Inf", true
+	case math.IsInf(f, -1):
+		return "-Inf", true
+	}
+	return "", false
+}
+
+// floatFromSpecial returns the value named by floatSpecial, T must be float32 or float64.
+func floatFromSpecial[T any](name string) (T, bool) {
+	var f float64
+	switch name {
+	case "NaN":
+		f = math.NaN()
+	case "+Inf":
+		f = math.Inf(1)
+	case "-Inf":
+		f = math.Inf(-1)
+	default:
+		return *new(T), false
+	}
+	var v any = f
+	if _, ok := any(*new(T)).(float32); ok {
+		v = float32(f)
+	}
+	return v.(T), true
+}
@@ -0,0 +1,105 @@
+package skipset
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	var v struct {
+		A *Int64Set
+		B *OrderedSetDesc[int]
+		C *StringSet
+		D *OrderedSet[int]
+	}
+	if err := json.Unmarshal([]byte(`{"A":[3,1,2,3],"B":[1,3,2],"C":["b","a"],"D":[]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int64](t, v.A, []int64{1, 2, 3})
+	checkSet[int](t, v.B, []int{3, 2, 1})
+	checkSet[string](t, v.C, []string{"a", "b"})
+	checkSet[int](t, v.D, nil)
+	v.A.Add(0) // the decoded skip set is usable
+	checkSet[int64](t, v.A, []int64{0, 1, 2, 3})
+	data, err := json.Marshal(v)
+	if err != nil || string(data) != `{"A":[0,1,2,3],"B":[3,2,1],"C":["a","b"],"D":[]}` {
+		t.Fatal("invalid marshal", string(data), err)
+	}
+
+	// Decoding replaces the values, null is ignored.
+	if err := json.Unmarshal([]byte(`{"A":[5]}`), &v); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.B.UnmarshalJSON([]byte("null")); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int64](t, v.A, []int64{5})
+	checkSet[int](t, v.B, []int{3, 2, 1})
+	if err := json.Unmarshal([]byte(`{"A":["x"]}`), &v); err == nil {
+		t.Fatal("invalid value should fail")
+	}
+
+	// Zero-value skip sets.
+	var z OrderedSet[int]
+	if data, err := json.Marshal(&z); err != nil || string(data) != "[]" {
+		t.Fatal("invalid marshal", string(data), err)
+	}
+	if err := json.Unmarshal([]byte(`[2,1]`), &z); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int](t, &z, []int{1, 2})
+}
+
+func TestJSONFunc(t *testing.T) {
+	// NaN and ±Inf.
+	f := NewFloat64()
+	for _, x := range []float64{2, math.Inf(1), math.NaN(), math.Inf(-1), 1} {
+		f.Add(x)
+	}
+	data, err := json.Marshal(f)
+	if err != nil || string(data) != `["NaN","-Inf",1,2,"+Inf"]` {
+		t.Fatal("invalid marshal", string(data), err)
+	}
+	f2 := NewFloat64()
+	if err := json.Unmarshal(data, f2); err != nil {
+		t.Fatal(err)
+	}
+	if data2, err := json.Marshal(f2); err != nil || string(data2) != string(data) {
+		t.Fatal("invalid round trip", string(data2), err)
+	}
+	f32 := NewFloat32Desc()
+	if err := json.Unmarshal([]byte(`[1,"NaN",2.5,"-Inf"]`), f32); err != nil {
+		t.Fatal(err)
+	}
+	var got []float32
+	f32.Range(func(v float32) bool {
+		got = append(got, v)
+		return true
+	})
+	if len(got) != 4 || !isNaNf32(got[0]) || got[1] != 2.5 || got[2] != 1 || !math.IsInf(float64(got[3]), -1) {
+		t.Fatal("invalid unmarshal", got)
+	}
+	if err := json.Unmarshal([]byte(`["Inf"]`), f32); err == nil {
+		t.Fatal("invalid float name accepted")
+	}
+
+	// The less function must be set.
+	var z FuncSet[int]
+	if err := json.Unmarshal([]byte(`[1]`), &z); err != errNoLess {
+		t.Fatal("invalid error", err)
+	}
+}
+
+func TestJSONMulti(t *testing.T) {
+	s := NewStringMulti()
+	if err := json.Unmarshal([]byte(`["b","a","b","c","b"]`), s); err != nil {
+		t.Fatal(err)
+	}
+	checkMultiSet(t, s.Range, []string{"a", "b", "c"}, []int{1, 3, 1})
+	s.Add("a")
+	data, err := json.Marshal(s)
+	if err != nil || string(data) != `["a","a","b","b","b","c"]` {
+		t.Fatal("invalid marshal", string(data), err)
+	}
+}
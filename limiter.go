@@ -0,0 +1,67 @@
+package skipset
+
+import (
+	"sync"
+	"time"
+)
+
+// SlidingWindowLimiter allows at most limit events in any sliding window of the given duration.
+// It records the timestamp of each allowed event in an Int64Set, and prunes the expired ones
+// by splitting the skip set in O(log n) time rather than removing them one by one. The length
+// of the remaining events is then counted by walking the shorter part, usually the expired events.
+type SlidingWindowLimiter struct {
+	mu     sync.Mutex // serializes pruning and recording
+	clock  Clock
+	limit  int
+	window int64
+	last   int64 // the key of the latest event, keys are unique even within a nanosecond
+	events *Int64Set
+}
+
+// NewSlidingWindowLimiter returns a limiter allowing limit events per window, using the given
+// clock, or the system clock if it is nil.
+func NewSlidingWindowLimiter(limit int, window time.Duration, clock Clock) *SlidingWindowLimiter {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &SlidingWindowLimiter{
+		clock:  clock,
+		limit:  limit,
+		window: int64(window),
+		events: NewInt64(),
+	}
+}
+
+// prune drops the events which are not in the window ending at now. The skip set is only split
+// if the first event has expired.
+func (l *SlidingWindowLimiter) prune(now int64) {
+	if x := l.events.header.loadNext(0); x == nil || x.value > now-l.window {
+		return
+	}
+	l.events = l.events.SplitAt(now - l.window + 1)
+}
+
+// Allow reports whether an event may happen now, and records it if so.
+func (l *SlidingWindowLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now().UnixNano()
+	l.prune(now)
+	if l.events.Len() >= l.limit {
+		return false
+	}
+	if now <= l.last {
+		now = l.last + 1
+	}
+	l.events.Add(now)
+	l.last = now
+	return true
+}
+
+// Count returns the number of events in the current window.
+func (l *SlidingWindowLimiter) Count() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(l.clock.Now().UnixNano())
+	return l.events.Len()
+}
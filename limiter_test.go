@@ -0,0 +1,72 @@
+package skipset
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSlidingWindowLimiter(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(100, 0)}
+	l := NewSlidingWindowLimiter(3, time.Second, clock)
+	for i := 0; i < 3; i++ {
+		// Several events within the same nanosecond.
+		if !l.Allow() {
+			t.Fatal("should allow", i)
+		}
+	}
+	if l.Allow() || l.Count() != 3 {
+		t.Fatal("should not allow")
+	}
+	clock.Advance(time.Second - 1)
+	if l.Allow() {
+		t.Fatal("should not allow before the window slides")
+	}
+	clock.Advance(1)
+	if l.Count() != 2 {
+		t.Fatal("invalid count", l.Count())
+	}
+	// The keys of the first events were bumped by 1ns each.
+	if !l.Allow() || l.Allow() {
+		t.Fatal("invalid allow")
+	}
+	clock.Advance(2)
+	if l.Count() != 1 || !l.Allow() || !l.Allow() || l.Allow() {
+		t.Fatal("invalid allow")
+	}
+	clock.Advance(time.Hour)
+	if l.Count() != 0 {
+		t.Fatal("invalid count", l.Count())
+	}
+
+	// Nothing is split if no event has expired.
+	l.Allow()
+	if n := testing.AllocsPerRun(100, func() { l.Count() }); n != 0 {
+		t.Fatal("invalid allocations", n)
+	}
+}
+
+func TestSlidingWindowLimiterConcurrent(t *testing.T) {
+	const limit = 100
+	l := NewSlidingWindowLimiter(limit, time.Hour, nil)
+	var (
+		wg      sync.WaitGroup
+		allowed int64
+	)
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if l.Allow() {
+					atomic.AddInt64(&allowed, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if allowed != limit || l.Count() != limit {
+		t.Fatal("invalid count", allowed, l.Count())
+	}
+}
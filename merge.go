@@ -0,0 +1,50 @@
+package skipset
+
+// mergeHeap is a binary min-heap of level-0 cursors, used to walk several
+// skip sets in lockstep.
+type mergeHeap[N any] struct {
+	nodes []N
+	less  func(a, b N) bool
+}
+
+func (h *mergeHeap[N]) push(n N) {
+	h.nodes = append(h.nodes, n)
+	i := len(h.nodes) - 1
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.nodes[i], h.nodes[parent]) {
+			break
+		}
+		h.nodes[i], h.nodes[parent] = h.nodes[parent], h.nodes[i]
+		i = parent
+	}
+}
+
+// fix restores the heap after the minimum has been replaced.
+func (h *mergeHeap[N]) fix() {
+	i, n := 0, len(h.nodes)
+	for {
+		min, l, r := i, 2*i+1, 2*i+2
+		if l < n && h.less(h.nodes[l], h.nodes[min]) {
+			min = l
+		}
+		if r < n && h.less(h.nodes[r], h.nodes[min]) {
+			min = r
+		}
+		if min == i {
+			return
+		}
+		h.nodes[i], h.nodes[min] = h.nodes[min], h.nodes[i]
+		i = min
+	}
+}
+
+// pop removes the minimum.
+func (h *mergeHeap[N]) pop() {
+	last := len(h.nodes) - 1
+	h.nodes[0] = h.nodes[last]
+	var zero N
+	h.nodes[last] = zero
+	h.nodes = h.nodes[:last]
+	h.fix()
+}
@@ -0,0 +1,112 @@
+package skipset
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestMerge(t *testing.T) {
+	a, b, c := NewString(), NewString(), NewString()
+	for _, v := range []string{"b", "d", "f"} {
+		a.Add(v)
+	}
+	for _, v := range []string{"a", "d", "g"} {
+		b.Add(v)
+	}
+	for _, v := range []string{"c", "f", "h"} {
+		c.Add(v)
+	}
+	checkMerge(t, MergeString(a, b, c), []string{"a", "b", "c", "d", "f", "g", "h"})
+	checkMerge(t, MergeString(a, NewString()), []string{"b", "d", "f"})
+	checkMerge(t, MergeString(), []string{})
+
+	// Stop the iteration.
+	var got []string
+	MergeString(a, b, c)(func(value string) bool {
+		got = append(got, value)
+		return len(got) < 3
+	})
+	if !slicesEqual(got, []string{"a", "b", "c"}) {
+		t.Fatal(got)
+	}
+
+	// Descending order.
+	x, y := NewInt64Desc(), NewInt64Desc()
+	for _, v := range []int64{-3, 1, 5} {
+		x.Add(v)
+	}
+	for _, v := range []int64{-3, 0, 7} {
+		y.Add(v)
+	}
+	checkMerge(t, MergeInt64Desc(x, y), []int64{7, 5, 1, 0, -3})
+
+	// Generic versions.
+	o1, o2 := New[int](), New[int]()
+	f1 := NewFunc(func(a, b int) bool { return a > b })
+	f2 := NewFunc(func(a, b int) bool { return a > b })
+	for i := 0; i < 100; i++ {
+		v := int(fastrand.Uint32n(50))
+		if i%2 == 0 {
+			o1.Add(v)
+			f1.Add(v)
+		} else {
+			o2.Add(v)
+			f2.Add(v)
+		}
+	}
+	var expected []int
+	for i := 0; i < 50; i++ {
+		if o1.Contains(i) || o2.Contains(i) {
+			expected = append(expected, i)
+		}
+	}
+	checkMerge(t, Merge(o1, o2), expected)
+	for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+		expected[i], expected[j] = expected[j], expected[i]
+	}
+	checkMerge(t, MergeFunc(f1, f2), expected)
+}
+
+func TestMergeConcurrent(t *testing.T) {
+	sets := []*StringSet{NewString(), NewString(), NewString()}
+	var wg sync.WaitGroup
+	for i := range sets {
+		wg.Add(1)
+		go func(s *StringSet) {
+			for j := 0; j < 1000; j++ {
+				v := strconv.Itoa(int(fastrand.Uint32n(100)))
+				if fastrand.Uint32n(2) == 0 {
+					s.Add(v)
+				} else {
+					s.Remove(v)
+				}
+			}
+			wg.Done()
+		}(sets[i])
+	}
+	for i := 0; i < 100; i++ {
+		var last string
+		MergeString(sets...)(func(value string) bool {
+			if last != "" && value <= last {
+				t.Fatal("invalid order", last, value)
+			}
+			last = value
+			return true
+		})
+	}
+	wg.Wait()
+}
+
+func checkMerge[T comparable](t *testing.T, merge func(f func(value T) bool), expected []T) {
+	got := []T{}
+	merge(func(value T) bool {
+		got = append(got, value)
+		return true
+	})
+	if !slicesEqual(got, expected) {
+		t.Fatalf("Expected: %v\n Got: %v\n", expected, got)
+	}
+}
@@ -0,0 +1,122 @@
+package skipset
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestMultiSet(t *testing.T) {
+	s := NewStringMulti()
+	if !s.Add("b") || s.Add("b") || !s.Add("a") || s.Add("b") {
+		t.Fatal("invalid add")
+	}
+	if s.Count("a") != 1 || s.Count("b") != 3 || s.Count("c") != 0 || s.Len() != 2 {
+		t.Fatal("invalid count")
+	}
+	checkMultiSet(t, s.Range, []string{"a", "b"}, []int{1, 3})
+
+	if !s.Remove("b") || s.Count("b") != 2 || !s.Contains("b") {
+		t.Fatal("invalid remove")
+	}
+	if !s.Remove("a") || s.Contains("a") || s.Remove("a") || s.Remove("c") || s.Len() != 1 {
+		t.Fatal("invalid remove")
+	}
+	s.Remove("b")
+	s.Remove("b")
+	if s.Contains("b") || s.Len() != 0 {
+		t.Fatal("invalid remove")
+	}
+	s.Add("b")
+	checkMultiSet(t, s.Range, []string{"b"}, []int{1})
+
+	// RangeFrom and descending order.
+	d := NewMultiDesc[int]()
+	for _, v := range []int{1, 5, 3, 5, 1, 5} {
+		d.Add(v)
+	}
+	checkMultiSet(t, d.Range, []int{5, 3, 1}, []int{3, 1, 2})
+	checkMultiSet(t, func(f func(value int, count int) bool) {
+		d.RangeFrom(4, f)
+	}, []int{3, 1}, []int{1, 2})
+
+	f := NewFuncMulti(func(a, b float64) bool { return a < b })
+	f.Add(0.5)
+	f.Add(0.5)
+	if f.Count(0.5) != 2 || f.Count(1) != 0 {
+		t.Fatal("invalid count")
+	}
+}
+
+func TestMultiSetConcurrent(t *testing.T) {
+	const (
+		goroutines = 16
+		n          = 1000
+	)
+	s := NewMulti[string]()
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				s.Add(strconv.Itoa(j % 10))
+			}
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		if c := s.Count(strconv.Itoa(i)); c != goroutines*n/10 {
+			t.Fatal("invalid count", i, c)
+		}
+	}
+
+	// Add and remove the same values concurrently, every removal matches an addition.
+	s = NewMulti[string]()
+	var removed, added [goroutines]int
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				v := strconv.Itoa(int(fastrand.Uint32n(3)))
+				if fastrand.Uint32n(2) == 0 {
+					s.Add(v)
+					added[i]++
+				} else if s.Remove(v) {
+					removed[i]++
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	var total int
+	for i := range added {
+		total += added[i] - removed[i]
+	}
+	s.Range(func(value string, count int) bool {
+		total -= count
+		return true
+	})
+	if total != 0 {
+		t.Fatal("invalid total count", total)
+	}
+}
+
+func checkMultiSet[T comparable](t *testing.T, rangefn func(f func(value T, count int) bool), values []T, counts []int) {
+	t.Helper()
+	var (
+		gotValues []T
+		gotCounts []int
+	)
+	rangefn(func(value T, count int) bool {
+		gotValues = append(gotValues, value)
+		gotCounts = append(gotCounts, count)
+		return true
+	})
+	if !slicesEqual(gotValues, values) || !slicesEqual(gotCounts, counts) {
+		t.Fatalf("Expected: %v %v\n Got: %v %v\n", values, counts, gotValues, gotCounts)
+	}
+}
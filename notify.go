@@ -0,0 +1,54 @@
+package skipset
+
+import (
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// notifier wakes up the goroutines waiting for new values in a skip set.
+type notifier struct {
+	mu      sync.Mutex
+	ch      chan struct{} // closed by the next broadcast
+	waiting uint32        // 1 if ch is not nil
+}
+
+// wait returns a channel which is closed by the next broadcast.
+func (n *notifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	if n.ch == nil {
+		n.ch = make(chan struct{})
+		atomic.StoreUint32(&n.waiting, 1)
+	}
+	ch := n.ch
+	n.mu.Unlock()
+	return ch
+}
+
+// broadcast wakes up all the waiters, it only costs an atomic load if there is none.
+func (n *notifier) broadcast() {
+	if atomic.LoadUint32(&n.waiting) == 0 {
+		return
+	}
+	n.mu.Lock()
+	if n.ch != nil {
+		close(n.ch)
+		n.ch = nil
+		atomic.StoreUint32(&n.waiting, 0)
+	}
+	n.mu.Unlock()
+}
+
+// loadNotifier returns the notifier stored in p, or nil if there is none.
+func loadNotifier(p *unsafe.Pointer) *notifier {
+	return (*notifier)(atomic.LoadPointer(p))
+}
+
+// loadOrStoreNotifier returns the notifier stored in p, storing a new one if there is none.
+func loadOrStoreNotifier(p *unsafe.Pointer) *notifier {
+	if n := loadNotifier(p); n != nil {
+		return n
+	}
+	atomic.CompareAndSwapPointer(p, nil, unsafe.Pointer(new(notifier)))
+	return loadNotifier(p)
+}
@@ -0,0 +1,98 @@
+package skipset
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFollow(t *testing.T) {
+	s := NewInt64()
+	for _, v := range []int64{1, 3, 5} {
+		s.Add(v)
+	}
+
+	var (
+		mu  sync.Mutex
+		got []int64
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- s.Follow(ctx, 2, func(value int64) bool {
+			mu.Lock()
+			got = append(got, value)
+			mu.Unlock()
+			return true
+		})
+	}()
+	waitFor := func(n int) {
+		for i := 0; ; i++ {
+			mu.Lock()
+			l := len(got)
+			mu.Unlock()
+			if l >= n {
+				return
+			}
+			if i > 1000 {
+				t.Fatalf("timeout, expected %d values, got %d", n, l)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	waitFor(2)
+	s.Add(4) // behind the cursor
+	s.Add(7)
+	waitFor(3)
+	s.Add(6)
+	s.Add(9)
+	waitFor(4)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatal(err)
+	}
+	if !slicesEqual(got, []int64{3, 5, 7, 9}) {
+		t.Fatal(got)
+	}
+
+	// Stop the iteration.
+	var stopped []int64
+	err := s.Follow(context.Background(), 0, func(value int64) bool {
+		stopped = append(stopped, value)
+		return value < 4
+	})
+	if err != nil || !slicesEqual(stopped, []int64{1, 3, 4}) {
+		t.Fatal(err, stopped)
+	}
+}
+
+func TestFollowConcurrent(t *testing.T) {
+	const n = 1000
+	s := NewIntDesc()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := n
+			err := s.Follow(ctx, n, func(value int) bool {
+				if value >= last {
+					panic("invalid order")
+				}
+				last = value
+				return value != 0
+			})
+			if err != nil {
+				panic(err)
+			}
+		}()
+	}
+	for i := n - 1; i >= 0; i-- {
+		s.Add(i)
+	}
+	wg.Wait()
+}
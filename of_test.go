@@ -0,0 +1,39 @@
+package skipset
+
+import (
+	"math"
+	"testing"
+)
+
+func TestOf(t *testing.T) {
+	checkSet[int](t, Of(3, 1, 2, 1), []int{1, 2, 3})
+	checkSet[int](t, OfDesc(3, 1, 2, 1), []int{3, 2, 1})
+	checkSet[string](t, OfString("b", "a", "b"), []string{"a", "b"})
+	checkSet[uint32](t, OfUint32Desc(), nil)
+	checkSet[int](t, OfFunc(func(a, b int) bool { return a%10 < b%10 }, 21, 2, 11, 3), []int{21, 2, 3})
+	checkMultiSet(t, OfStringMulti("b", "a", "b").Range, []string{"a", "b"}, []int{1, 2})
+	checkMultiSet(t, FromSliceMultiDesc([]int{1, 2, 2}).Range, []int{2, 1}, []int{2, 1})
+
+	// The input is not modified.
+	values := []int64{5, -1, 5, 0}
+	s := FromSliceInt64(values)
+	checkSet[int64](t, s, []int64{-1, 0, 5})
+	if !slicesEqual(values, []int64{5, -1, 5, 0}) {
+		t.Fatal("the input should not be modified", values)
+	}
+	if !s.Add(1) || s.Add(5) {
+		t.Fatal("the skip set should be usable")
+	}
+
+	// Floats, NaN comes first.
+	f := OfFloat64Desc(1, math.NaN(), 2, math.NaN(), 1)
+	var got []float64
+	f.Range(func(v float64) bool {
+		got = append(got, v)
+		return true
+	})
+	if len(got) != 3 || !math.IsNaN(got[0]) || got[1] != 2 || got[2] != 1 {
+		t.Fatal("invalid FuncSet", got)
+	}
+	checkSet[float32](t, FromSliceFloat32([]float32{2, -1, 2}), []float32{-1, 2})
+}
@@ -0,0 +1,81 @@
+package skipset
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// PriorityQueue is a concurrent priority queue based on a skip set ordered by a less function.
+//
+// The values of equal priority are popped in the order they were pushed. Multiple consumers
+// can pop concurrently, each value is delivered to exactly one of them.
+type PriorityQueue[T any] struct {
+	seq uint64 // breaks the ties between equal priorities
+	set *FuncSet[*pqItem[T]]
+}
+
+type pqItem[T any] struct {
+	value T
+	seq   uint64
+}
+
+// NewPriorityQueue returns an empty priority queue, the minimum is the first value in
+// the order of less.
+//
+// Note that the less function requires a strict weak ordering,
+// see https://en.wikipedia.org/wiki/Weak_ordering#Strict_weak_orderings,
+// or undefined behavior will happen.
+func NewPriorityQueue[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		set: NewFunc(func(a, b *pqItem[T]) bool {
+			return less(a.value, b.value) || (!less(b.value, a.value) && a.seq < b.seq)
+		}),
+	}
+}
+
+// Push adds the value into the queue and wakes up the consumers blocked in PopMin.
+func (q *PriorityQueue[T]) Push(value T) {
+	q.set.Add(&pqItem[T]{value: value, seq: atomic.AddUint64(&q.seq, 1)})
+}
+
+// TryPopMin removes and returns the minimum of the queue, it returns false if the queue is empty.
+func (q *PriorityQueue[T]) TryPopMin() (T, bool) {
+	for {
+		x := q.set.header.atomicLoadNextValid()
+		if x == nil {
+			var zero T
+			return zero, false
+		}
+		// Only one consumer can remove the node, the others try the next minimum.
+		if q.set.Remove(x.value) {
+			return x.value.value, true
+		}
+	}
+}
+
+// PopMin removes and returns the minimum of the queue, blocking until a value is available.
+// It returns ctx.Err() if ctx is done before that.
+func (q *PriorityQueue[T]) PopMin(ctx context.Context) (T, error) {
+	n := loadOrStoreNotifier(&q.set.notify)
+	for {
+		if value, ok := q.TryPopMin(); ok {
+			return value, nil
+		}
+		ch := n.wait()
+		// Check again, a value may have been pushed before getting the channel.
+		if value, ok := q.TryPopMin(); ok {
+			return value, nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of values in the queue.
+func (q *PriorityQueue[T]) Len() int {
+	return q.set.Len()
+}
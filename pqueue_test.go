@@ -0,0 +1,110 @@
+package skipset
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPriorityQueue(t *testing.T) {
+	q := NewPriorityQueue(func(a, b time.Duration) bool { return a < b })
+	if _, ok := q.TryPopMin(); ok {
+		t.Fatal("invalid pop")
+	}
+	for _, v := range []time.Duration{3, 1, 2, 1} {
+		q.Push(v)
+	}
+	if q.Len() != 4 {
+		t.Fatal("invalid length")
+	}
+	for _, expected := range []time.Duration{1, 1, 2} {
+		if v, ok := q.TryPopMin(); !ok || v != expected {
+			t.Fatal("invalid pop", v, expected)
+		}
+	}
+	if v, err := q.PopMin(context.Background()); err != nil || v != 3 {
+		t.Fatal("invalid pop", v, err)
+	}
+
+	// Block until a value is pushed.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Push(4)
+	}()
+	if v, err := q.PopMin(context.Background()); err != nil || v != 4 {
+		t.Fatal("invalid pop", v, err)
+	}
+
+	// Context done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.PopMin(ctx); err != context.DeadlineExceeded {
+		t.Fatal("invalid error", err)
+	}
+}
+
+func TestPriorityQueueEqualPriorities(t *testing.T) {
+	q := NewPriorityQueue(func(a, b [2]int) bool { return a[0] < b[0] })
+	q.Push([2]int{1, 1})
+	q.Push([2]int{0, 3})
+	q.Push([2]int{1, 2})
+	if q.Len() != 3 {
+		t.Fatal("invalid length", q.Len())
+	}
+	// Equal priorities are popped in the order they were pushed.
+	for _, expected := range [][2]int{{0, 3}, {1, 1}, {1, 2}} {
+		if v, ok := q.TryPopMin(); !ok || v != expected {
+			t.Fatal("invalid pop", v, expected)
+		}
+	}
+	if q.Len() != 0 {
+		t.Fatal("invalid length", q.Len())
+	}
+}
+
+func TestPriorityQueueConcurrent(t *testing.T) {
+	const (
+		producers = 4
+		consumers = 8
+		n         = 1000
+	)
+	q := NewPriorityQueue(func(a, b int) bool { return a < b })
+	var (
+		wg        sync.WaitGroup
+		delivered [producers * n]int32
+		count     int64
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	for i := 0; i < consumers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				v, err := q.PopMin(ctx)
+				if err != nil {
+					return
+				}
+				if atomic.AddInt32(&delivered[v], 1) != 1 {
+					panic("double delivery")
+				}
+				if atomic.AddInt64(&count, 1) == producers*n {
+					cancel()
+				}
+			}
+		}()
+	}
+	for i := 0; i < producers; i++ {
+		go func(i int) {
+			for j := 0; j < n; j++ {
+				q.Push(i*n + j)
+			}
+		}(i)
+	}
+	wg.Wait()
+	if count != producers*n {
+		t.Fatal("invalid count", count)
+	}
+}
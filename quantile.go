@@ -0,0 +1,46 @@
+package skipset
+
+import (
+	"math"
+	"sort"
+)
+
+// quantileSamples is the minimum expected number of nodes in the level scanned
+// by Quantiles. Sets with fewer than quantileSamples/p elements are scanned at
+// level 0, so their quantiles are exact.
+const quantileSamples = 1024
+
+// quantileLevel returns the level scanned by Quantiles for a set with n elements.
+// The level is the highest one (below highestLevel) expected to hold at least
+// quantileSamples nodes.
+func quantileLevel(n, highestLevel int) int {
+	level := 0
+	for level+1 < highestLevel && float64(n)*math.Pow(p, float64(level+1)) >= quantileSamples {
+		level++
+	}
+	return level
+}
+
+// quantileRanks returns the 0-based rank of every quantile in qs for a level with
+// count nodes, and the indexes of qs ordered by rank. It reports false if any q
+// is outside [0, 1].
+func quantileRanks(qs []float64, count int) (ranks, order []int, ok bool) {
+	ranks = make([]int, len(qs))
+	order = make([]int, len(qs))
+	for i, q := range qs {
+		if !(q >= 0 && q <= 1) { // also rejects NaN
+			return nil, nil, false
+		}
+		// Nearest-rank method: the smallest rank r with (r+1)/count >= q.
+		r := int(math.Ceil(q*float64(count))) - 1
+		if r < 0 {
+			r = 0
+		}
+		ranks[i] = r
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return ranks[order[i]] < ranks[order[j]]
+	})
+	return ranks, order, true
+}
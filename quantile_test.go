@@ -0,0 +1,100 @@
+package skipset
+
+import (
+	"math"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestQuantile(t *testing.T) {
+	// Empty set and invalid quantiles.
+	s := NewInt64()
+	if _, ok := s.Quantile(0.5); ok {
+		t.Fatal("empty set should not have quantiles")
+	}
+	s.Add(1)
+	for _, q := range []float64{-0.1, 1.1, math.NaN()} {
+		if _, ok := s.Quantile(q); ok {
+			t.Fatal("invalid quantile", q)
+		}
+	}
+	if _, ok := s.Quantiles(0.5, 2); ok {
+		t.Fatal("invalid quantiles")
+	}
+
+	// Small sets are exact.
+	s = NewInt64()
+	for i := int64(1); i <= 100; i++ {
+		s.Add(i)
+	}
+	checkQuantiles(t, s.Quantiles, []float64{0, 0.01, 0.5, 0.9, 0.99, 1}, []int64{1, 1, 50, 90, 99, 100})
+	sd := NewInt64Desc()
+	for i := int64(1); i <= 100; i++ {
+		sd.Add(i)
+	}
+	checkQuantiles(t, sd.Quantiles, []float64{1, 0, 0.5}, []int64{1, 100, 51})
+
+	f := NewFloat64()
+	for _, v := range []float64{3, 1, 2, 4} {
+		f.Add(v)
+	}
+	checkQuantiles(t, f.Quantiles, []float64{0.25, 0.5, 0.75, 1}, []float64{1, 2, 3, 4})
+
+	// Large sets are estimated.
+	const n = 1 << 18
+	u := NewUint64()
+	for i := uint64(0); i < n; i++ {
+		u.Add(i)
+	}
+	// The endpoints are exact.
+	if vs, ok := u.Quantiles(1, 0.5, 0); !ok || vs[0] != n-1 || vs[2] != 0 {
+		t.Fatal("invalid endpoints", vs)
+	}
+	if v, ok := u.Quantile(0); !ok || v != 0 {
+		t.Fatal("invalid min", v)
+	}
+	if v, ok := u.Quantile(1); !ok || v != n-1 {
+		t.Fatal("invalid max", v)
+	}
+	for _, q := range []float64{0.1, 0.5, 0.9, 0.99} {
+		v, ok := u.Quantile(q)
+		if !ok {
+			t.Fatal("invalid quantile")
+		}
+		if diff := math.Abs(float64(v) - q*n); diff > 0.08*n {
+			t.Fatalf("quantile %v: got %v, expected about %v", q, v, q*n)
+		}
+	}
+}
+
+func TestQuantileConcurrent(t *testing.T) {
+	s := NewInt()
+	for i := 0; i < 10000; i++ {
+		s.Add(i)
+	}
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10000; i++ {
+			s.Remove(int(fastrand.Uint32n(10000)))
+		}
+		close(done)
+	}()
+	for {
+		select {
+		case <-done:
+			return
+		default:
+			if v, ok := s.Quantile(0.5); ok && (v < 0 || v >= 10000) {
+				t.Fatal("invalid quantile", v)
+			}
+		}
+	}
+}
+
+func checkQuantiles[T comparable](t *testing.T, quantiles func(qs ...float64) ([]T, bool), qs []float64, expected []T) {
+	got, ok := quantiles(qs...)
+	if !ok || !slicesEqual(got, expected) {
+		t.Fatalf("Expected: %v (quantiles %v)\n Got: %v\n", expected, qs, got)
+	}
+}
@@ -0,0 +1,133 @@
+package skipset
+
+import (
+	"hash/maphash"
+	"reflect"
+	"runtime"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Sharded represents a set based on several skip sets in ascending order, values are
+// partitioned among them by hash. It spreads the contention of write-heavy workloads, at the
+// cost of merging the skip sets in Range and RangeFrom.
+type Sharded[T ordered] struct {
+	seed     maphash.Seed
+	isString bool
+	mask     uint64
+	shards   []*OrderedSet[T]
+}
+
+// NewSharded returns an empty sharded set with at least n shards, n is rounded up to a
+// power of two. If n <= 0, the number of shards is derived from GOMAXPROCS.
+func NewSharded[T ordered](n int) *Sharded[T] {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	size := 1
+	for size < n {
+		size <<= 1
+	}
+	var t T
+	s := &Sharded[T]{
+		seed:     maphash.MakeSeed(),
+		isString: reflect.TypeOf(t).Kind() == reflect.String,
+		mask:     uint64(size - 1),
+		shards:   make([]*OrderedSet[T], size),
+	}
+	for i := range s.shards {
+		s.shards[i] = New[T]()
+	}
+	return s
+}
+
+// shard returns the skip set which holds value.
+func (s *Sharded[T]) shard(value T) *OrderedSet[T] {
+	var h maphash.Hash
+	h.SetSeed(s.seed)
+	if s.isString {
+		h.WriteString(*(*string)(unsafe.Pointer(&value)))
+	} else {
+		var zero T
+		if value == zero {
+			value = zero // -0.0 and +0.0 are equal but have different bits
+		}
+		h.Write(unsafe.Slice((*byte)(unsafe.Pointer(&value)), unsafe.Sizeof(value)))
+	}
+	return s.shards[h.Sum64()&s.mask]
+}
+
+// Add adds the value into the set, returns true if this process inserts the value into the set,
+// returns false if this process can't insert this value, because another process has inserted the same value.
+func (s *Sharded[T]) Add(value T) bool {
+	return s.shard(value).Add(value)
+}
+
+// Contains checks if the value is in the set.
+func (s *Sharded[T]) Contains(value T) bool {
+	return s.shard(value).Contains(value)
+}
+
+// Remove removes a node from the set.
+func (s *Sharded[T]) Remove(value T) bool {
+	return s.shard(value).Remove(value)
+}
+
+// Range calls f sequentially for each value present in the set, in ascending order.
+// If f returns false, range stops the iteration.
+func (s *Sharded[T]) Range(f func(value T) bool) {
+	s.rangeMerge(func(set *OrderedSet[T]) *orderednode[T] {
+		return set.header.atomicLoadNextValid()
+	}, f)
+}
+
+// RangeFrom calls f sequentially for all values with `value >= start` in the set, in ascending order.
+// If f returns false, range stops the iteration.
+func (s *Sharded[T]) RangeFrom(start T, f func(value T) bool) {
+	s.rangeMerge(func(set *OrderedSet[T]) *orderednode[T] {
+		x := set.header
+		for i := int(atomic.LoadUint64(&set.highestLevel)) - 1; i >= 0; i-- {
+			for next := x.atomicLoadNext(i); next != nil && next.value < start; next = x.atomicLoadNext(i) {
+				x = next
+			}
+		}
+		return x.atomicLoadNextValid()
+	}, f)
+}
+
+// rangeMerge walks the level-0 lists of the shards in lockstep, each one from the node
+// returned by first.
+func (s *Sharded[T]) rangeMerge(first func(set *OrderedSet[T]) *orderednode[T], f func(value T) bool) {
+	h := mergeHeap[*orderednode[T]]{
+		nodes: make([]*orderednode[T], 0, len(s.shards)),
+		less: func(a, b *orderednode[T]) bool {
+			return a.value < b.value
+		},
+	}
+	for _, set := range s.shards {
+		if x := first(set); x != nil {
+			h.push(x)
+		}
+	}
+	for len(h.nodes) != 0 {
+		x := h.nodes[0]
+		if !f(x.value) {
+			return
+		}
+		if next := x.atomicLoadNextValid(); next != nil {
+			h.nodes[0] = next
+			h.fix()
+		} else {
+			h.pop()
+		}
+	}
+}
+
+// Len returns the length of this set.
+func (s *Sharded[T]) Len() int {
+	var length int
+	for _, set := range s.shards {
+		length += set.Len()
+	}
+	return length
+}
@@ -0,0 +1,48 @@
+package skipset
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSharded(t *testing.T) {
+	testIntSet(t, func() anyskipset[int] {
+		return NewSharded[int](0)
+	})
+	testStringSet(t, func() anyskipset[string] {
+		return NewSharded[string](3)
+	})
+
+	s := NewSharded[float64](8)
+	if len(s.shards) != 8 {
+		t.Fatal("invalid shard count", len(s.shards))
+	}
+	s.Add(0)
+	if !s.Contains(math.Copysign(0, -1)) || s.Add(math.Copysign(0, -1)) {
+		t.Fatal("-0 and +0 should be equal")
+	}
+	for i := 100; i > 0; i-- {
+		s.Add(float64(i))
+	}
+	var got []float64
+	s.RangeFrom(90.5, func(v float64) bool {
+		got = append(got, v)
+		return len(got) < 5
+	})
+	if !slicesEqual(got, []float64{91, 92, 93, 94, 95}) {
+		t.Fatal("invalid RangeFrom", got)
+	}
+	got = got[:0]
+	s.Range(func(v float64) bool {
+		got = append(got, v)
+		return true
+	})
+	if len(got) != 101 || s.Len() != 101 {
+		t.Fatal("invalid length", len(got), s.Len())
+	}
+	for i, v := range got {
+		if v != float64(i) {
+			t.Fatal("invalid Range", got)
+		}
+	}
+}
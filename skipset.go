@@ -5,7 +5,14 @@
 //go:generate go run gen.go
 package skipset
 
-import "math"
+import (
+	"errors"
+	"math"
+)
+
+// ErrNotSorted is returned by the FromSorted constructors when the values are not in the order
+// of the skip set.
+var ErrNotSorted = errors.New("skipset: values are not sorted")
 
 // New returns an empty skip set in ascending order.
 func New[T ordered]() *OrderedSet[T] {
@@ -97,6 +104,58 @@ func NewFloat64Desc() *FuncSet[float64] {
 	})
 }
 
+// OfFloat32 returns a skip set with the given values in any order, see FromSliceFloat32.
+func OfFloat32(values ...float32) *FuncSet[float32] {
+	return FromSliceFloat32(values)
+}
+
+// FromSliceFloat32 returns a skip set with the given values in any order, duplicate values are
+// added once. A copy of the values is sorted, then the skip list is built in linear time.
+func FromSliceFloat32(values []float32) *FuncSet[float32] {
+	s := NewFloat32()
+	s.reset(append([]float32(nil), values...))
+	return s
+}
+
+// OfFloat32Desc returns a skip set with the given values in any order, see FromSliceFloat32Desc.
+func OfFloat32Desc(values ...float32) *FuncSet[float32] {
+	return FromSliceFloat32Desc(values)
+}
+
+// FromSliceFloat32Desc returns a skip set with the given values in any order, duplicate values are
+// added once. A copy of the values is sorted, then the skip list is built in linear time.
+func FromSliceFloat32Desc(values []float32) *FuncSet[float32] {
+	s := NewFloat32Desc()
+	s.reset(append([]float32(nil), values...))
+	return s
+}
+
+// OfFloat64 returns a skip set with the given values in any order, see FromSliceFloat64.
+func OfFloat64(values ...float64) *FuncSet[float64] {
+	return FromSliceFloat64(values)
+}
+
+// FromSliceFloat64 returns a skip set with the given values in any order, duplicate values are
+// added once. A copy of the values is sorted, then the skip list is built in linear time.
+func FromSliceFloat64(values []float64) *FuncSet[float64] {
+	s := NewFloat64()
+	s.reset(append([]float64(nil), values...))
+	return s
+}
+
+// OfFloat64Desc returns a skip set with the given values in any order, see FromSliceFloat64Desc.
+func OfFloat64Desc(values ...float64) *FuncSet[float64] {
+	return FromSliceFloat64Desc(values)
+}
+
+// FromSliceFloat64Desc returns a skip set with the given values in any order, duplicate values are
+// added once. A copy of the values is sorted, then the skip list is built in linear time.
+func FromSliceFloat64Desc(values []float64) *FuncSet[float64] {
+	s := NewFloat64Desc()
+	s.reset(append([]float64(nil), values...))
+	return s
+}
+
 // NewInt returns an empty skip set in ascending order.
 func NewInt() *IntSet {
 	h := newIntNode(0, maxLevel)
@@ -216,3 +275,61 @@ func NewUintDesc() *UintSetDesc {
 		highestLevel: defaultHighestLevel,
 	}
 }
+
+// NewMulti returns an empty skip multiset in ascending order.
+func NewMulti[T ordered]() *MultiSet[T] {
+	var t T
+	h := newMultiNode(t, maxLevel)
+	h.flags.SetTrue(fullyLinked)
+	return &MultiSet[T]{
+		header:       h,
+		highestLevel: defaultHighestLevel,
+	}
+}
+
+// NewMultiDesc returns an empty skip multiset in descending order.
+func NewMultiDesc[T ordered]() *MultiSetDesc[T] {
+	var t T
+	h := newMultiNodeDesc(t, maxLevel)
+	h.flags.SetTrue(fullyLinked)
+	return &MultiSetDesc[T]{
+		header:       h,
+		highestLevel: defaultHighestLevel,
+	}
+}
+
+// NewFuncMulti returns an empty skip multiset in ascending order.
+//
+// Note that the less function requires a strict weak ordering,
+// see https://en.wikipedia.org/wiki/Weak_ordering#Strict_weak_orderings,
+// or undefined behavior will happen.
+func NewFuncMulti[T any](less func(a, b T) bool) *FuncMultiSet[T] {
+	var t T
+	h := newFuncMultiNode(t, maxLevel)
+	h.flags.SetTrue(fullyLinked)
+	return &FuncMultiSet[T]{
+		header:       h,
+		highestLevel: defaultHighestLevel,
+		less:         less,
+	}
+}
+
+// NewStringMulti returns an empty skip multiset in ascending order.
+func NewStringMulti() *StringMultiSet {
+	h := newStringMultiNode("", maxLevel)
+	h.flags.SetTrue(fullyLinked)
+	return &StringMultiSet{
+		header:       h,
+		highestLevel: defaultHighestLevel,
+	}
+}
+
+// NewStringMultiDesc returns an empty skip multiset in descending order.
+func NewStringMultiDesc() *StringMultiSetDesc {
+	h := newStringMultiNodeDesc("", maxLevel)
+	h.flags.SetTrue(fullyLinked)
+	return &StringMultiSetDesc{
+		header:       h,
+		highestLevel: defaultHighestLevel,
+	}
+}
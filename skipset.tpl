@@ -6,12 +6,20 @@ import (
 	{{.Imports}}
 )
 
+{{- if .Multiset}}
+// {{.StructPrefix}}Set{{.StructSuffix}} represents a multiset based on skip list, each value has a count of occurrences.
+{{- else}}
 // {{.StructPrefix}}Set{{.StructSuffix}} represents a set based on skip list.
+{{- end}}
 type {{.StructPrefix}}Set{{.StructSuffix}}{{.TypeParam}} struct {
 	length       int64
 	highestLevel uint64 // highest level for now
 	header       *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
-    {{.ExtraFileds}}
+{{- if not .Multiset}}
+	notify       unsafe.Pointer // *notifier, stored by the first follower
+	pending      unsafe.Pointer // *lengthCounter, stored by SplitAt until the length is counted
+{{- end}}
+{{- .ExtraFileds}}
 }
 
 type {{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeParam}} struct {
@@ -20,12 +28,18 @@ type {{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeParam}} struct {
 	next  optionalArray // [level]*{{.StructPrefixLow}}node{{.StructSuffix}}
 	mu    sync.Mutex
 	level uint32
+{{- if .Multiset}}
+	count int64 // the number of occurrences, zero if the node is being deleted
+{{- end}}
 }
 
 func new{{.StructPrefix}}Node{{.StructSuffix}}{{.TypeParam}}(value {{.Type}}, level int) *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}} {
 	n := &{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}{
 		value: value,
 		level: uint32(level),
+{{- if .Multiset}}
+		count: 1,
+{{- end}}
 	}
 	if level > op1 {
 		n.next.extra = new([op2]unsafe.Pointer)
@@ -49,6 +63,44 @@ func (n *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}) atomicStore
 	n.next.atomicStore(i, unsafe.Pointer(next))
 }
 
+// atomicLoadNextValid returns the first fully linked and unmarked node after n in level 0.
+func (n *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}) atomicLoadNextValid() *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}} {
+	x := n.atomicLoadNext(0)
+	for x != nil && !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+		x = x.atomicLoadNext(0)
+	}
+	return x
+}
+{{- if .Multiset}}
+
+// incrementCount increments the count of n, it returns false if the count has dropped to zero.
+func (n *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}) incrementCount() bool {
+	for {
+		c := atomic.LoadInt64(&n.count)
+		if c == 0 {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&n.count, c, c+1) {
+			return true
+		}
+	}
+}
+
+// decrementCount decrements the count of n and returns the new count,
+// it returns -1 if the count has already dropped to zero.
+func (n *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}) decrementCount() int64 {
+	for {
+		c := atomic.LoadInt64(&n.count)
+		if c == 0 {
+			return -1
+		}
+		if atomic.CompareAndSwapInt64(&n.count, c, c-1) {
+			return c - 1
+		}
+	}
+}
+{{- end}}
+
 // findNodeRemove takes a value and two maximal-height arrays then searches exactly as in a sequential skip-list.
 // The returned preds and succs always satisfy preds[i] > value >= succs[i].
 func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) findNodeRemove(value {{.Type}}, preds *[maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}, succs *[maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}) int {
@@ -92,6 +144,146 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) findNodeAdd(val
 	return -1
 }
 
+// newEmpty returns an empty skip set in the same order as s.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) newEmpty() *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	return New{{.NewSuffix}}{{.TypeArgument}}({{if .HasLess}}s.less{{end}})
+}
+
+// {{.StructPrefixLow}}builder{{.StructSuffix}} appends values to a skip set in linear time.
+// The skip set must not be accessed by other goroutines until the build is done.
+type {{.StructPrefixLow}}builder{{.StructSuffix}}{{.TypeParam}} struct {
+	s    *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}
+	tail [maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}} // the last node before or at the last appended value in each level
+}
+
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) newBuilder() *{{.StructPrefixLow}}builder{{.StructSuffix}}{{.TypeArgument}} {
+	b := &{{.StructPrefixLow}}builder{{.StructSuffix}}{{.TypeArgument}}{s: s}
+	for i := range b.tail {
+		b.tail[i] = s.header
+	}
+	return b
+}
+
+// append adds the value right after the last appended value, the value must be greater than
+// it and less than the next value in the skip set (in the order of the skip set).
+func (b *{{.StructPrefixLow}}builder{{.StructSuffix}}{{.TypeArgument}}) append(value {{.Type}}) {
+	level := b.s.randomlevel()
+	nn := new{{.StructPrefix}}Node{{.StructSuffix}}(value, level)
+	for i := 0; i < level; i++ {
+		nn.storeNext(i, b.tail[i].loadNext(i))
+		b.tail[i].storeNext(i, nn)
+		b.tail[i] = nn
+	}
+	nn.flags.SetTrue(fullyLinked)
+	b.s.length++
+}
+
+// insert adds the value wherever it belongs in the skip set, it returns false if the value is
+{{- if .Multiset}}
+// already in the skip set, in which case its count is incremented.
+{{- else}}
+// already in the skip set.
+{{- end}}
+// The search starts from the last appended value, so inserting values in the order of the skip set
+// takes linear time.
+func (b *{{.StructPrefixLow}}builder{{.StructSuffix}}{{.TypeArgument}}) insert(value {{.Type}}) bool {
+	s, last := b.s, b.tail[0]
+	if next := last.loadNext(0); (last != s.header && !{{Less "last.value" "value"}}) || (next != nil && !{{Less "value" "next.value"}}) {
+		// The value doesn't follow the last appended value, search it from the header.
+		for i := range b.tail {
+			b.tail[i] = s.header
+		}
+		var succs [maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
+		if lFound := s.findNodeAdd(value, &b.tail, &succs); lFound != -1 {
+{{- if .Multiset}}
+			succs[lFound].count++
+{{- end}}
+			// The search stopped at level lFound, b.tail is only valid for the header.
+			for i := range b.tail {
+				b.tail[i] = s.header
+			}
+			return false
+		}
+	}
+	b.append(value)
+	return true
+}
+
+// reset replaces the values of s with the given ones, which are sorted in place then appended in linear time,
+{{- if .Multiset}}
+// repeated values are counted as occurrences.
+{{- else}}
+// repeated values are added once.
+{{- end}}
+// It requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) reset(values []{{.Type}}) {
+{{- if not .Multiset}}
+	s.countLength()
+{{- end}}
+	sort.Slice(values, func(i, j int) bool {
+		return {{Less "values[i]" "values[j]"}}
+	})
+	n := s.newEmpty()
+	b := n.newBuilder()
+	for i, v := range values {
+		if i > 0 && {{Equal "v" "values[i-1]"}} {
+{{- if .Multiset}}
+			b.tail[0].count++
+{{- end}}
+			continue
+		}
+		b.append(v)
+	}
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+}
+
+// New{{.NewSuffix}}FromSorted returns a skip set with the given values, which must be in the order of
+{{- if .Multiset}}
+// the skip set. Repeated values are counted as occurrences.
+{{- else}}
+// the skip set. Duplicate values are added once.
+{{- end}}
+// The skip list is built in linear time, with the same random levels as if the values were added
+// one by one. It returns ErrNotSorted if a value is out of order.
+func New{{.NewSuffix}}FromSorted{{.TypeParam}}({{if .HasLess}}less func(a, b T) bool, {{end}}values []{{.Type}}) (*{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}, error) {
+	s := New{{.NewSuffix}}{{.TypeArgument}}({{if .HasLess}}less{{end}})
+	b := s.newBuilder()
+	for i, v := range values {
+		if i > 0 {
+			if {{Less "v" "values[i-1]"}} {
+				return nil, ErrNotSorted
+			}
+			if !{{Less "values[i-1]" "v"}} {
+{{- if .Multiset}}
+				b.tail[0].count++
+{{- end}}
+				continue
+			}
+		}
+		b.append(v)
+	}
+	return s, nil
+}
+
+// Of{{.NewSuffix}} returns a skip set with the given values in any order, see FromSlice{{.NewSuffix}}.
+func Of{{.NewSuffix}}{{.TypeParam}}({{if .HasLess}}less func(a, b T) bool, {{end}}values ...{{.Type}}) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	return FromSlice{{.NewSuffix}}({{if .HasLess}}less, {{end}}values)
+}
+
+// FromSlice{{.NewSuffix}} returns a skip set with the given values in any order,
+{{- if .Multiset}}
+// repeated values are counted as occurrences.
+{{- else}}
+// duplicate values are added once.
+{{- end}}
+// A copy of the values is sorted in the order of the skip set, then the skip list is built
+// in linear time.
+func FromSlice{{.NewSuffix}}{{.TypeParam}}({{if .HasLess}}less func(a, b T) bool, {{end}}values []{{.Type}}) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	s := New{{.NewSuffix}}{{.TypeArgument}}({{if .HasLess}}less{{end}})
+	s.reset(append([]{{.Type}}(nil), values...))
+	return s
+}
+
 func unlock{{.Name}}{{.TypeParam}}(preds [maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}, highestLevel int) {
 	var prevPred *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
 	for i := highestLevel; i >= 0; i-- {
@@ -102,11 +294,19 @@ func unlock{{.Name}}{{.TypeParam}}(preds [maxLevel]*{{.StructPrefixLow}}node{{.S
 	}
 }
 
+{{- if .Multiset}}
+// Add adds an occurrence of the value into skip set, returns true if this process insert the value into skip set,
+// returns false if the value is already in the skip set, in which case its count is incremented.
+{{- else}}
 // Add adds the value into skip set, returns true if this process insert the value into skip set,
 // returns false if this process can't insert this value, because another process has insert the same value.
+{{- end}}
 //
 // If the value is in the skip set but not fully linked, this process will wait until it is.
 func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Add(value {{.Type}}) bool {
+{{- if not .Multiset}}
+	s.countLength()
+{{- end}}
 	level := s.randomlevel()
 	var preds, succs [maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
 	for {
@@ -117,7 +317,16 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Add(value {{.Ty
 				for !nodeFound.flags.Get(fullyLinked) {
 					// The node is not yet fully linked, just waits until it is.
 				}
+{{- if .Multiset}}
+				if nodeFound.incrementCount() {
+					return false
+				}
+				// The count has dropped to zero, represents some other thread is going to delete this node,
+				// we need to add this node in next loop.
+				continue
+{{- else}}
 				return false
+{{- end}}
 			}
 			// If the node is marked, represents some other thread is in the process of deleting this node,
 			// we need to add this node in next loop.
@@ -156,6 +365,11 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Add(value {{.Ty
 		nn.flags.SetTrue(fullyLinked)
 		unlock{{.Name}}(preds, highestLocked)
 		atomic.AddInt64(&s.length, 1)
+{{- if not .Multiset}}
+		if n := loadNotifier(&s.notify); n != nil {
+			n.broadcast()
+		}
+{{- end}}
 		return true
 	}
 }
@@ -178,6 +392,13 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) randomlevel() i
 
 // Contains checks if the value is in the skip set.
 func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Contains(value {{.Type}}) bool {
+{{- if .Multiset}}
+	return s.Count(value) != 0
+}
+
+// Count returns the number of occurrences of the value in the skip set.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Count(value {{.Type}}) int {
+{{- end}}
 	x := s.header
 	for i := int(atomic.LoadUint64(&s.highestLevel)) - 1; i >= 0; i-- {
 		nex := x.atomicLoadNext(i)
@@ -188,14 +409,33 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Contains(value
 
 		// Check if the value already in the skip list.
 		if nex != nil && {{Equal "nex.value" "value"}} {
+{{- if .Multiset}}
+			if !nex.flags.MGet(fullyLinked|marked, fullyLinked) {
+				return 0
+			}
+			return int(atomic.LoadInt64(&nex.count))
+{{- else}}
 			return nex.flags.MGet(fullyLinked|marked, fullyLinked)
+{{- end}}
 		}
 	}
+{{- if .Multiset}}
+	return 0
+{{- else}}
 	return false
+{{- end}}
 }
 
+{{- if .Multiset}}
+// Remove removes an occurrence of the value from the skip set, the node is removed
+// once its count drops to zero.
+{{- else}}
 // Remove removes a node from the skip set.
+{{- end}}
 func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Remove(value {{.Type}}) bool {
+{{- if not .Multiset}}
+	s.countLength()
+{{- end}}
 	var (
 		nodeToRemove *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
 		isMarked     bool // represents if this operation mark the node
@@ -209,6 +449,12 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Remove(value {{
 			if !isMarked { // we don't mark this node for now
 				nodeToRemove = succs[lFound]
 				topLayer = lFound
+{{- if .Multiset}}
+				if c := nodeToRemove.decrementCount(); c != 0 {
+					// Other occurrences remain, or the last one has been removed by another process.
+					return c > 0
+				}
+{{- end}}
 				nodeToRemove.mu.Lock()
 				if nodeToRemove.flags.Get(marked) {
 					// The node is marked by another process,
@@ -257,25 +503,42 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Remove(value {{
 	}
 }
 
+{{- if .Multiset}}
+// Range calls f sequentially for each value present in the skip set with its count.
+{{- else}}
 // Range calls f sequentially for each value present in the skip set.
+{{- end}}
 // If f returns false, range stops the iteration.
-func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Range(f func(value {{.Type}}) bool) {
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Range(f func(value {{.Type}}{{if .Multiset}}, count int{{end}}) bool) {
 	x := s.header.atomicLoadNext(0)
 	for x != nil {
 		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
 			x = x.atomicLoadNext(0)
 			continue
 		}
+{{- if .Multiset}}
+		count := atomic.LoadInt64(&x.count)
+		if count == 0 {
+			x = x.atomicLoadNext(0)
+			continue
+		}
+		if !f(x.value, int(count)) {
+{{- else}}
 		if !f(x.value) {
+{{- end}}
 			break
 		}
 		x = x.atomicLoadNext(0)
 	}
 }
 
+{{- if .Multiset}}
+// RangeFrom calls f sequentially for all values with `value >= start` in the skip set with their counts.
+{{- else}}
 // RangeFrom calls f sequentially for all values with `value >= start` in the skip set.
+{{- end}}
 // If f returns false, range stops the iteration.
-func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) RangeFrom(start {{.Type}}, f func(value {{.Type}}) bool) {
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) RangeFrom(start {{.Type}}, f func(value {{.Type}}{{if .Multiset}}, count int{{end}}) bool) {
 	var (
 		x   = s.header
 		nex *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
@@ -297,14 +560,853 @@ func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) RangeFrom(start
 			nex = nex.atomicLoadNext(0)
 			continue
 		}
+{{- if .Multiset}}
+		count := atomic.LoadInt64(&nex.count)
+		if count == 0 {
+			nex = nex.atomicLoadNext(0)
+			continue
+		}
+		if !f(nex.value, int(count)) {
+{{- else}}
 		if !f(nex.value) {
+{{- end}}
 			break
 		}
 		nex = nex.atomicLoadNext(0)
 	}
 }
 
+{{- if .Multiset}}
+// Len returns the number of distinct values in this skip set.
+{{- else}}
 // Len returns the length of this skip set.
+{{- end}}
 func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Len() int {
+{{- if not .Multiset}}
+	s.countLength()
+{{- end}}
 	return int(atomic.LoadInt64(&s.length))
 }
+
+// last returns the last value in the skip set, it returns false if the skip set is empty.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) last() ({{.Type}}, bool) {
+	// Only step onto valid nodes in the upper levels, so that x is never after the last valid node.
+	x := s.header
+	for i := int(atomic.LoadUint64(&s.highestLevel)) - 1; i > 0; i-- {
+		for next := x.atomicLoadNext(i); next != nil && next.flags.MGet(fullyLinked|marked, fullyLinked); next = x.atomicLoadNext(i) {
+			x = next
+		}
+	}
+	last := x
+	for x = x.atomicLoadNextValid(); x != nil; x = x.atomicLoadNextValid() {
+		last = x
+	}
+	if last == s.header {
+		var zero {{.Type}}
+		return zero, false
+	}
+	return last.value, true
+}
+{{- if not .Multiset}}
+
+// Follow calls f sequentially for all values with `value >= start` in the skip set, like RangeFrom,
+// then waits for values greater than the last value passed to f and calls f for them as they are
+// added, like `tail -f`. Values added behind the last value passed to f are skipped.
+//
+// Follow returns nil once f returns false, or ctx.Err() once ctx is done. It does not spin while
+// waiting: Add wakes up the followers after inserting a value.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Follow(ctx context.Context, start {{.Type}}, f func(value {{.Type}}) bool) error {
+	var (
+		n       = loadOrStoreNotifier(&s.notify)
+		cursor  = start
+		started bool // cursor has been passed to f
+		stopped bool
+	)
+	next := func(value {{.Type}}) bool {
+		if started && !{{Less "cursor" "value"}} {
+			return true
+		}
+		cursor, started = value, true
+		if !f(value) {
+			stopped = true
+			return false
+		}
+		return true
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Get the channel before scanning, so that a value added after the scan
+		// always closes it.
+		ch := n.wait()
+		s.RangeFrom(cursor, next)
+		if stopped {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+
+// Quantile returns the q-quantile (0 <= q <= 1) of the skip set, that is the element whose
+// rank in the skip set is ceil(q*n) for n elements. Quantile(0) and Quantile(1) are always
+// exactly the first and the last element. It returns false if the skip set is empty or q is
+// out of range.
+//
+// The skip set does not maintain span counts, so only sets with fewer than 4096 elements
+// are walked at level 0 and get an exact answer for every q. Larger sets are estimated from the highest
+// level expected to hold at least 1024 nodes: every element reaches that level independently
+// with the same probability, so the sampled quantile is off by at most about
+// sqrt(q*(1-q)/1024) (1.6% of the rank range for the median) in one standard deviation.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Quantile(q float64) ({{.Type}}, bool) {
+	res, ok := s.Quantiles(q)
+	if !ok {
+		var zero {{.Type}}
+		return zero, false
+	}
+	return res[0], true
+}
+
+// Quantiles is like Quantile but estimates several quantiles with a single scan.
+// The results are in the same order as qs. It returns false if the skip set is empty
+// or any q is out of range.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Quantiles(qs ...float64) ([]{{.Type}}, bool) {
+	level := quantileLevel(s.Len(), int(atomic.LoadUint64(&s.highestLevel)))
+	var count int
+	for x := s.header.atomicLoadNext(level); x != nil; x = x.atomicLoadNext(level) {
+		if x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			count++
+		}
+	}
+	ranks, order, ok := quantileRanks(qs, count)
+	if !ok || count == 0 {
+		return nil, false
+	}
+	var (
+		res   = make([]{{.Type}}, len(qs))
+		i     int
+		rank  int
+		last  {{.Type}}
+		found bool
+	)
+	for x := s.header.atomicLoadNext(level); x != nil && i < len(order); x = x.atomicLoadNext(level) {
+		if !x.flags.MGet(fullyLinked|marked, fullyLinked) {
+			continue
+		}
+		for i < len(order) && ranks[order[i]] == rank {
+			res[order[i]] = x.value
+			i++
+		}
+		last, found = x.value, true
+		rank++
+	}
+	if i < len(order) {
+		// Some elements have been removed since counting.
+		if !found {
+			return nil, false
+		}
+		for ; i < len(order); i++ {
+			res[order[i]] = last
+		}
+	}
+	if level != 0 {
+		// The endpoints are cheap to find exactly: the first node of level 0 and the tail.
+		for i, q := range qs {
+			switch q {
+			case 0:
+				if x := s.header.atomicLoadNextValid(); x != nil {
+					res[i] = x.value
+				}
+			case 1:
+				if v, ok := s.last(); ok {
+					res[i] = v
+				}
+			}
+		}
+	}
+	return res, true
+}
+
+// Merge{{.NewSuffix}} returns a function that calls f sequentially for each value present in
+// any of the skip sets, in the order of the skip sets. Values present in several skip sets are
+// passed to f only once. If f returns false, the iteration stops.
+//
+// The level-0 lists of the skip sets are walked in lockstep without copying any data, so the
+// iteration observes concurrent modifications the same way Range does.
+{{- if .HasLess}}
+//
+// All skip sets must share the same ordering, the less function of the first one is used.
+{{- end}}
+func Merge{{.NewSuffix}}{{.TypeParam}}(sets ...*{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) func(f func(value {{.Type}}) bool) {
+	return func(f func(value {{.Type}}) bool) {
+		if len(sets) != 0 {
+			sets[0].rangeMerge(sets, f)
+		}
+	}
+}
+
+// rangeMerge calls f for each distinct value of sets, using the ordering of s.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) rangeMerge(sets []*{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}, f func(value {{.Type}}) bool) {
+	h := mergeHeap[*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}]{
+		nodes: make([]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}, 0, len(sets)),
+		less: func(a, b *{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}) bool {
+			return {{Less "a.value" "b.value"}}
+		},
+	}
+	for _, set := range sets {
+		if x := set.header.atomicLoadNextValid(); x != nil {
+			h.push(x)
+		}
+	}
+	var (
+		last    {{.Type}}
+		emitted bool
+	)
+	for len(h.nodes) != 0 {
+		x := h.nodes[0]
+		if !emitted || {{Less "last" "x.value"}} {
+			if !f(x.value) {
+				return
+			}
+			last, emitted = x.value, true
+		}
+		if next := x.atomicLoadNextValid(); next != nil {
+			h.nodes[0] = next
+			h.fix()
+		} else {
+			h.pop()
+		}
+	}
+}
+
+// Union{{.NewSuffix}} returns a new skip set with the values present in a or b.
+//
+// Union{{.NewSuffix}}, Intersect{{.NewSuffix}}, Difference{{.NewSuffix}} and SymmetricDifference{{.NewSuffix}} merge-walk
+// the level-0 lists of both skip sets and build the result in linear time. The skip sets
+// may be modified concurrently, in which case the result observes the modifications the
+// same way Range does.
+{{- if .HasLess}}
+// The result uses the less function of a.
+{{- end}}
+func Union{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	return a.combine(b, true, true, true)
+}
+
+// Intersect{{.NewSuffix}} returns a new skip set with the values present in both a and b.
+func Intersect{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	return a.combine(b, false, true, false)
+}
+
+// Difference{{.NewSuffix}} returns a new skip set with the values present in a but not in b.
+func Difference{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	return a.combine(b, true, false, false)
+}
+
+// SymmetricDifference{{.NewSuffix}} returns a new skip set with the values present in exactly one of a and b.
+func SymmetricDifference{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	return a.combine(b, true, false, true)
+}
+
+// combine merge-walks s and other, the result contains the values only in s if onlyS is true,
+// the values in both if both is true, and the values only in other if onlyOther is true.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) combine(other *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}, onlyS, both, onlyOther bool) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	res := s.newEmpty()
+	b := res.newBuilder()
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for (x != nil || onlyOther) && (y != nil || onlyS) && (x != nil || y != nil) {
+		switch {
+		case y == nil || x != nil && {{Less "x.value" "y.value"}}:
+			if onlyS {
+				b.append(x.value)
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || {{Less "y.value" "x.value"}}:
+			if onlyOther {
+				b.append(y.value)
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			if both {
+				b.append(x.value)
+			}
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return res
+}
+
+// Equal{{.NewSuffix}} reports whether a and b contain the same values.
+//
+// Equal{{.NewSuffix}}, IsSubset{{.NewSuffix}}, IsSuperset{{.NewSuffix}} and Disjoint{{.NewSuffix}} merge-walk the level-0 lists
+// of both skip sets and return as soon as the answer is known. They compare the lengths first
+// when it can decide the answer, so if the skip sets are modified concurrently the result may
+// reflect any state of them during the call.
+func Equal{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	return a.isSubset(b, true)
+}
+
+// IsSubset{{.NewSuffix}} reports whether every value of a is in b.
+func IsSubset{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) bool {
+	if a.Len() > b.Len() {
+		return false
+	}
+	return a.isSubset(b, false)
+}
+
+// IsSuperset{{.NewSuffix}} reports whether every value of b is in a.
+func IsSuperset{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) bool {
+	return IsSubset{{.NewSuffix}}(b, a)
+}
+
+// Disjoint{{.NewSuffix}} reports whether a and b have no value in common.
+func Disjoint{{.NewSuffix}}{{.TypeParam}}(a, b *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) bool {
+	if a.Len() == 0 || b.Len() == 0 {
+		return true
+	}
+	return a.disjoint(b)
+}
+
+// disjoint reports whether s and other have no value in common.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) disjoint(other *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil && y != nil {
+		switch {
+		case {{Less "x.value" "y.value"}}:
+			x = x.atomicLoadNextValid()
+		case {{Less "y.value" "x.value"}}:
+			y = y.atomicLoadNextValid()
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isSubset reports whether every value of s is in other, and if equal is true,
+// whether every value of other is in s as well.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) isSubset(other *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}, equal bool) bool {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil {
+		switch {
+		case y == nil || {{Less "x.value" "y.value"}}:
+			return false // x is not in other
+		case {{Less "y.value" "x.value"}}:
+			if equal {
+				return false // y is not in s
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+	return !equal || y == nil
+}
+
+// Diff{{.NewSuffix}} reports the changes from oldSet to newSet in a single merge-walk of both
+// skip sets: onAdded is called for each value only in newSet and onRemoved for each value only
+// in oldSet, sequentially and in the order of the skip sets. A nil callback ignores its kind of
+// change. If a callback returns false, the iteration stops.
+func Diff{{.NewSuffix}}{{.TypeParam}}(oldSet, newSet *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}, onAdded, onRemoved func(value {{.Type}}) bool) {
+	oldSet.diff(newSet, func(value {{.Type}}, added bool) bool {
+		if added {
+			return onAdded == nil || onAdded(value)
+		}
+		return onRemoved == nil || onRemoved(value)
+	})
+}
+
+// DiffSeq{{.NewSuffix}} is like Diff{{.NewSuffix}} but returns the changes as an iterator of
+// (value, added) pairs, where added is false for the values removed from oldSet.
+func DiffSeq{{.NewSuffix}}{{.TypeParam}}(oldSet, newSet *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) func(yield func(value {{.Type}}, added bool) bool) {
+	return func(yield func(value {{.Type}}, added bool) bool) {
+		oldSet.diff(newSet, yield)
+	}
+}
+
+// diff calls yield for each value only in s (added is false) or only in other (added is true).
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) diff(other *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}, yield func(value {{.Type}}, added bool) bool) {
+	x, y := s.header.atomicLoadNextValid(), other.header.atomicLoadNextValid()
+	for x != nil || y != nil {
+		switch {
+		case y == nil || x != nil && {{Less "x.value" "y.value"}}:
+			if !yield(x.value, false) {
+				return
+			}
+			x = x.atomicLoadNextValid()
+		case x == nil || {{Less "y.value" "x.value"}}:
+			if !yield(y.value, true) {
+				return
+			}
+			y = y.atomicLoadNextValid()
+		default:
+			x, y = x.atomicLoadNextValid(), y.atomicLoadNextValid()
+		}
+	}
+}
+
+// SplitAt moves all the values with `value >= k` (in the order of the skip set) into a new
+// skip set and returns it. The towers are cut at the split point in O(log n) time. The lengths
+// of the two parts are counted later, by the first call to Len or to a method modifying either
+// part, which walks the shorter part.
+//
+// SplitAt requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) SplitAt(k {{.Type}}) *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}} {
+	s.countLength()
+	var preds, succs [maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
+	s.findNodeRemove(k, &preds, &succs)
+	right := s.newEmpty()
+	highestLevel := atomic.LoadUint64(&s.highestLevel)
+	for i := 0; i < int(highestLevel); i++ {
+		right.header.atomicStoreNext(i, succs[i])
+		preds[i].atomicStoreNext(i, nil)
+	}
+	atomic.StoreUint64(&right.highestLevel, highestLevel)
+
+	total := atomic.LoadInt64(&s.length)
+	c := &lengthCounter{count: func() {
+		// Count the shorter part by walking both parts in lockstep, neither part has been
+		// modified since the split.
+		var (
+			n    int64
+			l, r = s.header.loadNext(0), right.header.loadNext(0)
+		)
+		for l != nil && r != nil {
+			l, r = l.loadNext(0), r.loadNext(0)
+			n++
+		}
+		if r == nil {
+			atomic.StoreInt64(&right.length, n)
+			atomic.StoreInt64(&s.length, total-n)
+		} else {
+			atomic.StoreInt64(&right.length, total-n)
+			atomic.StoreInt64(&s.length, n)
+		}
+		atomic.StorePointer(&s.pending, nil)
+		atomic.StorePointer(&right.pending, nil)
+	}}
+	atomic.StorePointer(&s.pending, unsafe.Pointer(c))
+	atomic.StorePointer(&right.pending, unsafe.Pointer(c))
+	return right
+}
+
+// countLength counts the length of s if it was left pending by SplitAt, it must be called
+// before reading or modifying the length.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) countLength() {
+	if c := (*lengthCounter)(atomic.LoadPointer(&s.pending)); c != nil {
+		c.once.Do(c.count)
+	}
+}
+
+// Join moves all the values of right to the end of s in O(log n) time, leaving right empty.
+// The lengths left pending by SplitAt, if any, are counted first.
+// It returns false and does nothing if the first value of right is not greater than the last
+// value of s (in the order of the skip set).
+//
+// Join requires exclusive access, neither s nor right may be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Join(right *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) bool {
+	first := right.header.loadNext(0)
+	if first == nil {
+		return true
+	}
+	if right == s {
+		return false
+	}
+	s.countLength()
+	right.countLength()
+	// Find the last node in each level of s.
+	var (
+		tails [maxLevel]*{{.StructPrefixLow}}node{{.StructSuffix}}{{.TypeArgument}}
+		x     = s.header
+	)
+	for i := maxLevel - 1; i >= 0; i-- {
+		for next := x.loadNext(i); next != nil; next = x.loadNext(i) {
+			x = next
+		}
+		tails[i] = x
+	}
+	if x != s.header && !{{Less "x.value" "first.value"}} {
+		return false
+	}
+	rightLevel := atomic.LoadUint64(&right.highestLevel)
+	for i := 0; i < int(rightLevel); i++ {
+		tails[i].atomicStoreNext(i, right.header.loadNext(i))
+		right.header.atomicStoreNext(i, nil)
+	}
+	if rightLevel > atomic.LoadUint64(&s.highestLevel) {
+		atomic.StoreUint64(&s.highestLevel, rightLevel)
+	}
+	atomic.AddInt64(&s.length, atomic.SwapInt64(&right.length, 0))
+	return true
+}
+{{- end}}
+
+{{- if .Multiset}}
+
+// rangeOccurrences calls f sequentially for each occurrence of the values present in the skip set,
+// each value is passed count times. If f returns false, range stops the iteration.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) rangeOccurrences(f func(value {{.Type}}) bool) {
+	s.Range(func(value {{.Type}}, count int) bool {
+		for i := 0; i < count; i++ {
+			if !f(value) {
+				return false
+			}
+		}
+		return true
+	})
+}
+{{- end}}
+
+// MarshalJSON implements json.Marshaler. The skip set is encoded as a JSON array of its values
+{{- if .Multiset}}
+// in the order of the skip set, each value is repeated by its count.
+{{- else}}
+// in the order of the skip set.
+{{- end}}
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) MarshalJSON() ([]byte, error) {
+	if s.header == nil {
+		return []byte("[]"), nil
+	}
+{{- if .Multiset}}
+	return marshalJSON(s.rangeOccurrences)
+{{- else}}
+	return marshalJSON(s.Range)
+{{- end}}
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The values of the JSON array replace the values
+{{- if .Multiset}}
+// of the skip set, repeated values are counted as occurrences.
+{{- else}}
+// of the skip set.
+{{- end}}
+{{- if .HasLess}}
+// The less function can't be decoded, so the skip set must be created by New{{.NewSuffix}} before
+// unmarshalling, or an error is returned.
+{{- end}}
+//
+// UnmarshalJSON requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) UnmarshalJSON(data []byte) error {
+{{- if .HasLess}}
+	if s.less == nil {
+		return errNoLess
+	}
+{{- end}}
+	if string(data) == "null" {
+		return nil
+	}
+	values, err := unmarshalJSON[{{.Type}}](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder. The skip set is encoded as a slice of its values
+{{- if .Multiset}}
+// in the order of the skip set, each value is repeated by its count.
+{{- else}}
+// in the order of the skip set.
+{{- end}}
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) GobEncode() ([]byte, error) {
+	if s.header == nil {
+		return gobEncode[{{.Type}}](nil)
+	}
+{{- if .Multiset}}
+	return gobEncode(s.rangeOccurrences)
+{{- else}}
+	return gobEncode(s.Range)
+{{- end}}
+}
+
+// GobDecode implements gob.GobDecoder. The decoded values replace the values of the skip set.
+{{- if .HasLess}}
+// The less function can't be decoded, so the skip set must be created by New{{.NewSuffix}} before
+// decoding, e.g. by setting the field of a struct before decoding the struct, or an error is returned.
+{{- end}}
+//
+// GobDecode requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) GobDecode(data []byte) error {
+{{- if .HasLess}}
+	if s.less == nil {
+		return errNoLess
+	}
+{{- end}}
+	values, err := gobDecode[{{.Type}}](data)
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+{{- if .Integer}}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The values are encoded in the order of
+// the skip set, as the first value followed by the varint distances between consecutive values.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) MarshalBinary() ([]byte, error) {
+	if s.header == nil {
+		return []byte{binaryVersion, 0}, nil
+	}
+	return marshalBinary(s.Range), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The decoded values replace the values
+// of the skip set, they are checked to be in the order of the skip set and appended in one pass.
+//
+// UnmarshalBinary requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) UnmarshalBinary(data []byte) error {
+	n := s.newEmpty()
+	if err := unmarshalBinary(data, {{eq .StructSuffix "Desc"}}, n.newBuilder().append); err != nil {
+		return err
+	}
+	s.countLength()
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+	return nil
+}
+{{- end}}
+{{- if and (eq .Type "string") (not .Multiset)}}
+
+// MarshalBinary implements encoding.BinaryMarshaler. The values are encoded in the order of
+// the skip set with front coding: each value is stored as the length of the prefix it shares
+// with the previous value and the rest, with a complete value every 16 values.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) MarshalBinary() ([]byte, error) {
+	if s.header == nil {
+		return []byte{frontCodingVersion, 0}, nil
+	}
+	return marshalFrontCoded(s.Range), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler. The decoded values replace the values
+// of the skip set, they are checked to be in the order of the skip set and appended in one pass.
+//
+// UnmarshalBinary requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) UnmarshalBinary(data []byte) error {
+	n := s.newEmpty()
+	less := func(a, b string) bool {
+		return {{Less "a" "b"}}
+	}
+	if err := unmarshalFrontCoded(data, less, n.newBuilder().append); err != nil {
+		return err
+	}
+	s.countLength()
+	s.header, s.highestLevel, s.length = n.header, n.highestLevel, n.length
+	return nil
+}
+{{- end}}
+
+// WriteTo implements io.WriterTo, it writes the values to w like WriteToCodec with the default
+// codec of the value type: integers are encoded as varints, floats as their IEEE 754 bits,
+// strings as their length followed by their bytes. Other types require WriteToCodec.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) WriteTo(w io.Writer) (int64, error) {
+	codec, err := defaultCodec[{{.Type}}]()
+	if err != nil {
+		return 0, err
+	}
+	return s.WriteToCodec(w, codec)
+}
+
+// WriteToCodec writes the values to w in the order of the skip set
+{{- if .Multiset}}
+// as they are visited by Range, each value is repeated by its count.
+{{- else}}
+// as they are visited by Range.
+{{- end}}
+// The values are streamed without buffering the whole skip set, each one is encoded by codec
+// after a 1 byte, and a 0 byte marks the end of the stream.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) WriteToCodec(w io.Writer, codec Codec[{{.Type}}]) (int64, error) {
+	if s.header == nil {
+		return writeTo(w, codec, nil)
+	}
+{{- if .Multiset}}
+	return writeTo(w, codec, s.rangeOccurrences)
+{{- else}}
+	return writeTo(w, codec, s.Range)
+{{- end}}
+}
+
+// ReadFrom implements io.ReaderFrom, it reads values from r like ReadFromCodec with the
+// default codec of the value type, see WriteTo.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) ReadFrom(r io.Reader) (int64, error) {
+	codec, err := defaultCodec[{{.Type}}]()
+	if err != nil {
+		return 0, err
+	}
+	return s.ReadFromCodec(r, codec)
+}
+
+// ReadFromCodec reads the values written by WriteToCodec from r and adds them into the skip set,
+// it returns the number of bytes read. It stops right after the end of the stream, so several
+// skip sets can be read back-to-back from r. Unless r implements io.ByteReader, it is read one
+// byte at a time, wrap it in a bufio.Reader for speed.
+// Each value is inserted by searching from the previous one, so reading values in the order of the
+// skip set takes linear time.
+{{- if .HasLess}}
+// The less function can't be decoded, so the skip set must be created by New{{.NewSuffix}} before
+// reading, or an error is returned.
+{{- end}}
+//
+// ReadFromCodec requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) ReadFromCodec(r io.Reader, codec Codec[{{.Type}}]) (int64, error) {
+{{- if .HasLess}}
+	if s.less == nil {
+		return 0, errNoLess
+	}
+{{- end}}
+	if s.header == nil {
+		n := s.newEmpty()
+		s.header, s.highestLevel = n.header, n.highestLevel
+	}
+{{- if not .Multiset}}
+	s.countLength()
+{{- end}}
+	b := s.newBuilder()
+	return readFrom(r, codec, func(value {{.Type}}) {
+		b.insert(value)
+	})
+}
+{{- if and (eq .TypeArgument "") (not .Multiset)}}
+
+// MarshalText implements encoding.TextMarshaler. The skip set is encoded as a comma-separated
+// list of its values in the order of the skip set.
+{{- if eq .Type "string"}}
+// It returns an error if a value is empty, contains a comma or has surrounding spaces, since
+// UnmarshalText couldn't decode it.
+{{- end}}
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) MarshalText() ([]byte, error) {
+	if s.header == nil {
+		return []byte{}, nil
+	}
+{{- if .Integer}}
+	return appendText(nil, s.Range, appendInteger[{{.Type}}]), nil
+{{- else}}
+	if err := checkTextStrings(s.Range); err != nil {
+		return nil, err
+	}
+	return appendText(nil, s.Range, appendString), nil
+{{- end}}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. The values of the comma-separated list
+// replace the values of the skip set, see Set.
+//
+// UnmarshalText requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) UnmarshalText(text []byte) error {
+{{- if .Integer}}
+	values, err := parseText(string(text), parseInteger[{{.Type}}])
+{{- else}}
+	values, err := parseText(string(text), parseString)
+{{- end}}
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+
+// Set implements flag.Value, it adds the values of a comma-separated list into the skip set,
+// so that a repeated flag accumulates its values. The spaces around the values are trimmed and
+// the empty values are skipped. No value is added if one of them is invalid.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) Set(value string) error {
+{{- if .Integer}}
+	values, err := parseText(value, parseInteger[{{.Type}}])
+{{- else}}
+	values, err := parseText(value, parseString)
+{{- end}}
+	if err != nil {
+		return err
+	}
+	if s.header == nil {
+		n := s.newEmpty()
+		s.header, s.highestLevel = n.header, n.highestLevel
+	}
+	for _, v := range values {
+		s.Add(v)
+	}
+	return nil
+}
+
+// String implements flag.Value, it returns the values as a comma-separated list in the order of
+// the skip set, so that flag can print the default values in the syntax accepted by Set.
+// Use Format for a bounded output, e.g. with %v.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) String() string {
+	if s.header == nil {
+		return ""
+	}
+{{- if .Integer}}
+	return string(appendText(nil, s.Range, appendInteger[{{.Type}}]))
+{{- else}}
+	return string(appendText(nil, s.Range, appendString))
+{{- end}}
+}
+{{- end}}
+{{- if and (eq .TypeArgument "") (not .Multiset)}}
+
+// Value implements driver.Valuer. The skip set is encoded as a PostgreSQL array literal of its
+// values in the order of the skip set, e.g. {{if .Integer}}{1,2,3}{{else}}{"a","b"}{{end}}.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) Value() (driver.Value, error) {
+	var rangeFn func(f func(value {{.Type}}) bool)
+	if s.header != nil {
+		rangeFn = s.Range
+	}
+{{- if .Integer}}
+	return string(appendArray(nil, rangeFn, appendInteger[{{.Type}}], false)), nil
+{{- else}}
+	return string(appendArray(nil, rangeFn, appendString, true)), nil
+{{- end}}
+}
+
+// Scan implements sql.Scanner. The values of a PostgreSQL array literal, scanned from a string
+// or a []byte, replace the values of the skip set. A NULL column empties the skip set.
+//
+// Scan requires exclusive access, s must not be used by other goroutines during the call.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}) Scan(src any) error {
+{{- if .Integer}}
+	values, err := scanArray(src, parseInteger[{{.Type}}])
+{{- else}}
+	values, err := scanArray(src, parseString)
+{{- end}}
+	if err != nil {
+		return err
+	}
+	s.reset(values)
+	return nil
+}
+{{- end}}
+{{- if or (ne .TypeArgument "") .Multiset}}
+
+// String returns the values in the order of the skip set, e.g. {1, 2, 3}.
+// At most 16 values are shown, see Format.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) String() string {
+	return fmt.Sprint(s)
+}
+{{- end}}
+
+// Format implements fmt.Formatter. The values are formatted with the verb in the order of the
+// skip set, e.g. {1, 2, 3}{{if .Multiset}}, each one followed by its count, e.g. {a:2, b:1}{{end}}.
+// At most 16 values are shown, the precision sets another maximum, e.g. %.100v.
+// The plus flag adds the length, e.g. len=3 {1, 2, 3}.
+func (s *{{.StructPrefix}}Set{{.StructSuffix}}{{.TypeArgument}}) Format(f fmt.State, verb rune) {
+	if s.header == nil {
+		formatSet(f, verb, 0, nil)
+		return
+	}
+	formatSet(f, verb, s.Len(), func(emit func(value any, count int) bool) {
+{{- if .Multiset}}
+		s.Range(func(value {{.Type}}, count int) bool {
+			return emit(value, count)
+		})
+{{- else}}
+		s.Range(func(value {{.Type}}) bool {
+			return emit(value, -1)
+		})
+{{- end}}
+	})
+}
@@ -24,6 +24,10 @@ func BenchmarkInt64(b *testing.B) {
 				return a < b
 			})
 		}})
+	all = append(all, benchTask[int64]{
+		name: "skipset(sharded)", New: func() anyskipset[int64] {
+			return NewSharded[int64](0)
+		}})
 	all = append(all, benchTask[int64]{
 		name: "sync.Map", New: func() anyskipset[int64] {
 			return new(anySyncMap[int64])
@@ -0,0 +1,48 @@
+//go:build go1.21
+
+package skipset
+
+import "log/slog"
+
+// logSample is the maximum number of values in the sample logged by LogValue.
+const logSample = 8
+
+// logOrder tells logValue how the order of a skip set relates to the natural order of its values.
+type logOrder int
+
+const (
+	logAscending  logOrder = iota
+	logDescending          // the values are in reverse natural order
+	logLess                // the order of a less function, the values may have no natural order
+)
+
+// logValue returns a group with the length, the minimum and maximum values and a sample of the
+// first values of a skip set. The minimum is the first value passed to f by rangeFn and the
+// maximum the value returned by last, or the contrary if order is logDescending. If order is
+// logLess, they are logged as the first and last values instead. rangeFn and last may be nil
+// if the skip set is empty.
+func logValue[T any](length int, rangeFn func(f func(value T) bool), last func() (T, bool), order logOrder) slog.Value {
+	attrs := []slog.Attr{slog.Int("len", length)}
+	if rangeFn == nil {
+		return slog.GroupValue(attrs...)
+	}
+	sample := make([]T, 0, logSample)
+	rangeFn(func(value T) bool {
+		sample = append(sample, value)
+		return len(sample) < logSample
+	})
+	lastValue, ok := last()
+	if len(sample) == 0 || !ok {
+		return slog.GroupValue(attrs...)
+	}
+	switch order {
+	case logAscending:
+		attrs = append(attrs, slog.Any("min", sample[0]), slog.Any("max", lastValue))
+	case logDescending:
+		attrs = append(attrs, slog.Any("min", lastValue), slog.Any("max", sample[0]))
+	default:
+		attrs = append(attrs, slog.Any("first", sample[0]), slog.Any("last", lastValue))
+	}
+	attrs = append(attrs, slog.Any("sample", sample))
+	return slog.GroupValue(attrs...)
+}
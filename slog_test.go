@@ -0,0 +1,65 @@
+//go:build go1.21
+
+package skipset
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	s := NewInt64Desc()
+	for i := int64(0); i < 100; i++ {
+		s.Add(i)
+	}
+	m := NewStringMulti()
+	m.Add("b")
+	m.Add("a")
+	m.Add("a")
+	var z FuncSet[int]
+	logger.Info("sets", "ids", s, "tags", m, "empty", &z, "new", New[int]())
+	expected := "level=INFO msg=sets ids.len=100 ids.min=0 ids.max=99 ids.sample=\"[99 98 97 96 95 94 93 92]\" " +
+		"tags.len=2 tags.min=a tags.max=b tags.sample=\"[a b]\" empty.len=0 new.len=0\n"
+	if got := buf.String(); got != expected {
+		t.Fatalf("Expected: %s\n Got: %s", expected, got)
+	}
+
+	// The FuncSets log the first and last values, the less function may be descending.
+	buf.Reset()
+	logger.Info("funcs", "f", OfFloat64Desc(1, 5, 3), "m", OfFuncMulti(func(a, b int) bool { return a < b }, 2, 1, 2))
+	expected = "level=INFO msg=funcs f.len=3 f.first=5 f.last=1 f.sample=\"[5 3 1]\" " +
+		"m.len=2 m.first=1 m.last=2 m.sample=\"[1 2]\"\n"
+	if got := buf.String(); got != expected {
+		t.Fatalf("Expected: %s\n Got: %s", expected, got)
+	}
+}
+
+func TestLast(t *testing.T) {
+	s := New[int]()
+	if _, ok := s.last(); ok {
+		t.Fatal("empty set should not have a last value")
+	}
+	for i := 0; i < 1000; i++ {
+		s.Add(i)
+		if v, ok := s.last(); !ok || v != i {
+			t.Fatal("invalid last value", v, i)
+		}
+	}
+	for i := 999; i > 0; i-- {
+		s.Remove(i)
+		if v, ok := s.last(); !ok || v != i-1 {
+			t.Fatal("invalid last value", v, i-1)
+		}
+	}
+}
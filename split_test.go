@@ -0,0 +1,150 @@
+package skipset
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestSplitAtJoin(t *testing.T) {
+	const n = 1000
+	for _, k := range []int64{-1, 0, 1, 250, 500, 999, 1000, 2000} {
+		s := NewInt64()
+		for i := int64(0); i < n; i++ {
+			s.Add(i)
+		}
+		right := s.SplitAt(k)
+		var left, rest []int64
+		for i := int64(0); i < n; i++ {
+			if i < k {
+				left = append(left, i)
+			} else {
+				rest = append(rest, i)
+			}
+		}
+		checkSet(t, s, left)
+		checkSet(t, right, rest)
+		for i := int64(0); i < n; i++ {
+			if s.Contains(i) != (i < k) || right.Contains(i) != (i >= k) {
+				t.Fatal("invalid contains", i)
+			}
+		}
+
+		// Both parts are regular skip sets.
+		if len(left) > 0 && !s.Remove(left[len(left)-1]) {
+			t.Fatal("invalid remove")
+		}
+		if len(rest) > 0 && !right.Remove(rest[0]) {
+			t.Fatal("invalid remove")
+		}
+		s.Add(-10)
+		right.Add(n + 10)
+
+		if !s.Join(right) || right.Len() != 0 {
+			t.Fatal("invalid join")
+		}
+		var all []int64
+		s.Range(func(value int64) bool {
+			all = append(all, value)
+			return true
+		})
+		if s.Len() != len(all) || all[0] != -10 || all[len(all)-1] != n+10 {
+			t.Fatal("invalid join", k, s.Len(), len(all))
+		}
+		checkSet(t, right, []int64{})
+	}
+
+	// Join requires ordered sets.
+	a, b := NewStringDesc(), NewStringDesc()
+	a.Add("b")
+	b.Add("c")
+	if a.Join(b) || a.Len() != 1 || b.Len() != 1 {
+		t.Fatal("invalid join")
+	}
+	if !b.Join(a) || a.Len() != 0 {
+		t.Fatal("invalid join")
+	}
+	checkSet(t, b, []string{"c", "b"})
+	if !a.Join(b) || !a.Join(NewStringDesc()) || a.Join(a) {
+		t.Fatal("invalid join")
+	}
+	checkSet(t, a, []string{"c", "b"})
+
+	// Generic versions.
+	f := NewFloat64()
+	for i := 0; i < 100; i++ {
+		f.Add(float64(fastrand.Uint32n(1000)))
+	}
+	l := f.Len()
+	r := f.SplitAt(500)
+	if f.Len()+r.Len() != l {
+		t.Fatal("invalid length")
+	}
+	f.Range(func(value float64) bool {
+		if value >= 500 {
+			t.Fatal("invalid split")
+		}
+		return true
+	})
+	r.Range(func(value float64) bool {
+		if value < 500 {
+			t.Fatal("invalid split")
+		}
+		return true
+	})
+	if !f.Join(r) || f.Len() != l {
+		t.Fatal("invalid join")
+	}
+}
+
+func TestSplitAtPendingLength(t *testing.T) {
+	newSet := func() *Int64Set {
+		s := NewInt64()
+		for i := int64(0); i < 100; i++ {
+			s.Add(i)
+		}
+		return s
+	}
+
+	// The lengths are counted before the first modification of either part.
+	s := newSet()
+	r := s.SplitAt(30)
+	if !r.Add(200) || !s.Remove(0) || s.Len() != 29 || r.Len() != 71 {
+		t.Fatal("invalid length", s.Len(), r.Len())
+	}
+
+	// Splitting a part, or joining the parts, before counting.
+	s = newSet()
+	r = s.SplitAt(60)
+	r2 := r.SplitAt(90)
+	s2 := s.SplitAt(10)
+	if s.Len() != 10 || s2.Len() != 50 || r.Len() != 30 || r2.Len() != 10 {
+		t.Fatal("invalid length", s.Len(), s2.Len(), r.Len(), r2.Len())
+	}
+	s = newSet()
+	r = s.SplitAt(60)
+	if !s.Join(r) || s.Len() != 100 || r.Len() != 0 {
+		t.Fatal("invalid join", s.Len(), r.Len())
+	}
+
+	// Concurrent modifications of both parts.
+	s = newSet()
+	r = s.SplitAt(50)
+	var wg sync.WaitGroup
+	for i := int64(0); i < 8; i++ {
+		wg.Add(1)
+		go func(i int64) {
+			defer wg.Done()
+			for j := int64(0); j < 100; j++ {
+				s.Add(-1 - i*100 - j)
+				r.Add(100 + i*100 + j)
+				s.Len()
+			}
+		}(i)
+	}
+	wg.Wait()
+	if s.Len() != 850 || r.Len() != 850 {
+		t.Fatal("invalid length", s.Len(), r.Len())
+	}
+}
@@ -0,0 +1,155 @@
+package skipset
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	errArrayLiteral = errors.New("skipset: invalid array literal")
+	errArrayNull    = errors.New("skipset: NULL element in array literal")
+)
+
+// appendArray appends the values passed to f by rangeFn to buf as a PostgreSQL array literal,
+// e.g. {1,2,3}. If quote is true, the values are double-quoted and escaped.
+func appendArray[T any](buf []byte, rangeFn func(f func(value T) bool), format func(buf []byte, value T) []byte, quote bool) []byte {
+	buf = append(buf, '{')
+	var (
+		first   = true
+		scratch []byte
+	)
+	if rangeFn != nil {
+		rangeFn(func(value T) bool {
+			if !first {
+				buf = append(buf, ',')
+			}
+			first = false
+			if !quote {
+				buf = format(buf, value)
+				return true
+			}
+			scratch = format(scratch[:0], value)
+			buf = append(buf, '"')
+			for _, c := range scratch {
+				if c == '"' || c == '\\' {
+					buf = append(buf, '\\')
+				}
+				buf = append(buf, c)
+			}
+			buf = append(buf, '"')
+			return true
+		})
+	}
+	return append(buf, '}')
+}
+
+// parseArray parses the elements of a one-dimensional PostgreSQL array literal. The elements may
+// be double-quoted, and backslashes escape the next character. NULL elements are rejected.
+func parseArray(text string) ([]string, error) {
+	text = strings.TrimSpace(text)
+	if len(text) < 2 || text[0] != '{' || text[len(text)-1] != '}' {
+		return nil, errArrayLiteral
+	}
+	text = text[1 : len(text)-1]
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	var (
+		items []string
+		item  strings.Builder
+	)
+	for i := 0; ; {
+		// Parse an element, then expect a comma or the end.
+		for i < len(text) && isArraySpace(text[i]) {
+			i++
+		}
+		item.Reset()
+		quoted := i < len(text) && text[i] == '"'
+		if quoted {
+			i++
+			for ; i < len(text) && text[i] != '"'; i++ {
+				if text[i] == '\\' {
+					if i++; i == len(text) {
+						return nil, errArrayLiteral
+					}
+				}
+				item.WriteByte(text[i])
+			}
+			if i == len(text) {
+				return nil, errArrayLiteral
+			}
+			i++
+			for i < len(text) && isArraySpace(text[i]) {
+				i++
+			}
+		} else {
+			end, escaped := 0, false // the length of item without the trailing spaces
+			for ; i < len(text) && text[i] != ','; i++ {
+				switch c := text[i]; {
+				case c == '"' || c == '{' || c == '}':
+					return nil, errArrayLiteral
+				case c == '\\':
+					if i++; i == len(text) {
+						return nil, errArrayLiteral
+					}
+					item.WriteByte(text[i])
+					end, escaped = item.Len(), true
+				default:
+					item.WriteByte(c)
+					if !isArraySpace(c) {
+						end = item.Len()
+					}
+				}
+			}
+			s := item.String()[:end]
+			if s == "" {
+				return nil, errArrayLiteral
+			}
+			if !escaped && strings.EqualFold(s, "NULL") {
+				return nil, errArrayNull
+			}
+			item.Reset()
+			item.WriteString(s)
+		}
+		items = append(items, item.String())
+		if i == len(text) {
+			return items, nil
+		}
+		if text[i] != ',' {
+			return nil, errArrayLiteral
+		}
+		i++
+	}
+}
+
+func isArraySpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+// scanArray parses the values of a PostgreSQL array literal scanned from a database,
+// src may be a string, a []byte or nil.
+func scanArray[T any](src any, parse func(s string) (T, error)) ([]T, error) {
+	var text string
+	switch src := src.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		text = src
+	case []byte:
+		text = string(src)
+	default:
+		return nil, fmt.Errorf("skipset: cannot scan %T into a skip set", src)
+	}
+	items, err := parseArray(text)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]T, len(items))
+	for i, item := range items {
+		if values[i], err = parse(item); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,149 @@
+package skipset
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync"
+	"testing"
+)
+
+// fakeDriver is a database driver storing the last value passed to Exec, and returning it as
+// the only column of the only row of Query.
+type fakeDriver struct {
+	mu    sync.Mutex
+	value driver.Value
+}
+
+func (d *fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt(c), nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type fakeStmt struct{ d *fakeDriver }
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.value = args[0]
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	return &fakeRows{value: s.d.value}, nil
+}
+
+type fakeRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"tags"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	if s, ok := r.value.(string); ok {
+		dest[0] = []byte(s) // like the text protocol of most drivers
+	} else {
+		dest[0] = r.value
+	}
+	return nil
+}
+
+var fake = &fakeDriver{}
+
+func init() {
+	sql.Register("skipset-fake", fake)
+}
+
+func TestSQL(t *testing.T) {
+	db, err := sql.Open("skipset-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tags := NewString()
+	for _, v := range []string{"b", `with "quotes", \ and commas`, "", "NULL"} {
+		tags.Add(v)
+	}
+	if _, err := db.Exec("INSERT", tags); err != nil {
+		t.Fatal(err)
+	}
+	if fake.value != `{"","NULL","b","with \"quotes\", \\ and commas"}` {
+		t.Fatal("invalid value", fake.value)
+	}
+	got := NewString()
+	if err := db.QueryRow("SELECT").Scan(got); err != nil {
+		t.Fatal(err)
+	}
+	if !EqualString(tags, got) {
+		t.Fatal("invalid scan", got.String())
+	}
+
+	ids := NewInt64Desc()
+	ids.Add(-5)
+	ids.Add(10)
+	if _, err := db.Exec("INSERT", ids); err != nil {
+		t.Fatal(err)
+	}
+	if fake.value != "{10,-5}" {
+		t.Fatal("invalid value", fake.value)
+	}
+	var ids2 Int64SetDesc
+	if err := db.QueryRow("SELECT").Scan(&ids2); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int64](t, &ids2, []int64{10, -5})
+
+	// NULL columns.
+	if _, err := db.Exec("INSERT", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("SELECT").Scan(&ids2); err != nil || ids2.Len() != 0 {
+		t.Fatal("invalid scan", ids2.Len(), err)
+	}
+}
+
+func TestScanArray(t *testing.T) {
+	s := NewUint()
+	for text, expected := range map[string][]uint{
+		"{}":                     nil,
+		" { } ":                  nil,
+		"{1,2,3}":                {1, 2, 3},
+		`{ 3 , "1" ,2,2}`:        {1, 2, 3},
+		`{"4"}`:                  {4},
+		"{18446744073709551615}": {18446744073709551615},
+	} {
+		if err := s.Scan(text); err != nil {
+			t.Fatal(text, err)
+		}
+		checkSet[uint](t, s, expected)
+	}
+	for _, text := range []string{"", "1,2", "{1,,2}", "{1,}", "{{1}}", `{"1}`, "{NULL}", "{-1}", "{a}", `{1 "2"}`} {
+		if err := s.Scan(text); err == nil {
+			t.Fatal("invalid array literal should be rejected", text)
+		}
+	}
+	if err := s.Scan(1); err == nil {
+		t.Fatal("invalid type should be rejected")
+	}
+
+	str := NewString()
+	if err := str.Scan(`{a b , "c,d",\"e, nul\l}`); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[string](t, str, []string{`"e`, "a b", "c,d", "null"})
+}
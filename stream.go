@@ -0,0 +1,321 @@
+package skipset
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"unsafe"
+)
+
+// Codec encodes and decodes the values of a skip set in WriteToCodec and ReadFromCodec.
+// Decode must not read past the end of the value, so that the stream can be followed by other data.
+type Codec[T any] interface {
+	Encode(w *bufio.Writer, value T) error
+	Decode(r ByteReader) (T, error)
+}
+
+// ByteReader is the reader passed to Codec.Decode.
+type ByteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+var (
+	errNoCodec    = errors.New("skipset: no default codec for the value type, use WriteToCodec and ReadFromCodec")
+	errStreamData = errors.New("skipset: invalid stream data")
+)
+
+// The values of a stream are each preceded by streamValue, and the stream ends with streamEnd.
+const (
+	streamEnd   = 0
+	streamValue = 1
+)
+
+// writeTo writes the values passed to f by rangeFn to w, rangeFn may be nil if there is no value.
+func writeTo[T any](w io.Writer, codec Codec[T], rangeFn func(f func(value T) bool)) (int64, error) {
+	cw := &countingWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	var err error
+	if rangeFn != nil {
+		rangeFn(func(value T) bool {
+			if err = bw.WriteByte(streamValue); err == nil {
+				err = codec.Encode(bw, value)
+			}
+			return err == nil
+		})
+	}
+	if err == nil {
+		if err = bw.WriteByte(streamEnd); err == nil {
+			err = bw.Flush()
+		}
+	}
+	return cw.n, err
+}
+
+// readFrom decodes the values written by writeTo and passes them to f. It never reads r past
+// the end of the stream: r is read directly if it implements io.ByteReader, one byte at a time otherwise.
+func readFrom[T any](r io.Reader, codec Codec[T], f func(value T)) (int64, error) {
+	cr := &countingReader{r: r}
+	cr.br, _ = r.(io.ByteReader)
+	for {
+		b, err := cr.ReadByte()
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return cr.n, err
+		}
+		switch b {
+		case streamEnd:
+			return cr.n, nil
+		case streamValue:
+		default:
+			return cr.n, errStreamData
+		}
+		value, err := codec.Decode(cr)
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return cr.n, err
+		}
+		f(value)
+	}
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r  io.Reader
+	br io.ByteReader // r if it implements io.ByteReader
+	n  int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+func (r *countingReader) ReadByte() (byte, error) {
+	if r.br != nil {
+		b, err := r.br.ReadByte()
+		if err == nil {
+			r.n++
+		}
+		return b, err
+	}
+	var b [1]byte
+	_, err := io.ReadFull(r, b[:])
+	return b[0], err
+}
+
+// defaultCodec returns the codec used by WriteTo and ReadFrom for T. Integers are encoded as
+// varints, floats as their IEEE 754 bits in little endian, strings as their length followed by
+// their bytes.
+func defaultCodec[T any]() (Codec[T], error) {
+	var t T
+	switch k := reflect.TypeOf(&t).Elem().Kind(); k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return intCodec[T]{}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return uintCodec[T]{}, nil
+	case reflect.Float32, reflect.Float64:
+		return floatCodec[T]{}, nil
+	case reflect.String:
+		return stringCodec[T]{}, nil
+	}
+	return nil, errNoCodec
+}
+
+// intCodec encodes the signed integer types as varints.
+type intCodec[T any] struct{}
+
+func (intCodec[T]) Encode(w *bufio.Writer, value T) error {
+	var (
+		v   int64
+		p   = unsafe.Pointer(&value)
+		buf [binary.MaxVarintLen64]byte
+	)
+	switch unsafe.Sizeof(value) {
+	case 1:
+		v = int64(*(*int8)(p))
+	case 2:
+		v = int64(*(*int16)(p))
+	case 4:
+		v = int64(*(*int32)(p))
+	default:
+		v = *(*int64)(p)
+	}
+	_, err := w.Write(buf[:binary.PutVarint(buf[:], v)])
+	return err
+}
+
+func (intCodec[T]) Decode(r ByteReader) (T, error) {
+	var value T
+	v, err := binary.ReadVarint(r)
+	if err != nil {
+		return value, err
+	}
+	p := unsafe.Pointer(&value)
+	switch unsafe.Sizeof(value) {
+	case 1:
+		*(*int8)(p) = int8(v)
+		if int64(*(*int8)(p)) != v {
+			return value, errStreamData
+		}
+	case 2:
+		*(*int16)(p) = int16(v)
+		if int64(*(*int16)(p)) != v {
+			return value, errStreamData
+		}
+	case 4:
+		*(*int32)(p) = int32(v)
+		if int64(*(*int32)(p)) != v {
+			return value, errStreamData
+		}
+	default:
+		*(*int64)(p) = v
+	}
+	return value, nil
+}
+
+// uintCodec encodes the unsigned integer types as uvarints.
+type uintCodec[T any] struct{}
+
+func (uintCodec[T]) Encode(w *bufio.Writer, value T) error {
+	var (
+		v   uint64
+		p   = unsafe.Pointer(&value)
+		buf [binary.MaxVarintLen64]byte
+	)
+	switch unsafe.Sizeof(value) {
+	case 1:
+		v = uint64(*(*uint8)(p))
+	case 2:
+		v = uint64(*(*uint16)(p))
+	case 4:
+		v = uint64(*(*uint32)(p))
+	default:
+		v = *(*uint64)(p)
+	}
+	_, err := w.Write(buf[:binary.PutUvarint(buf[:], v)])
+	return err
+}
+
+func (uintCodec[T]) Decode(r ByteReader) (T, error) {
+	var value T
+	v, err := binary.ReadUvarint(r)
+	if err != nil {
+		return value, err
+	}
+	p := unsafe.Pointer(&value)
+	switch unsafe.Sizeof(value) {
+	case 1:
+		*(*uint8)(p) = uint8(v)
+		if uint64(*(*uint8)(p)) != v {
+			return value, errStreamData
+		}
+	case 2:
+		*(*uint16)(p) = uint16(v)
+		if uint64(*(*uint16)(p)) != v {
+			return value, errStreamData
+		}
+	case 4:
+		*(*uint32)(p) = uint32(v)
+		if uint64(*(*uint32)(p)) != v {
+			return value, errStreamData
+		}
+	default:
+		*(*uint64)(p) = v
+	}
+	return value, nil
+}
+
+// floatCodec encodes the float types as their IEEE 754 bits in little endian.
+type floatCodec[T any] struct{}
+
+func (floatCodec[T]) Encode(w *bufio.Writer, value T) error {
+	var (
+		buf [8]byte
+		p   = unsafe.Pointer(&value)
+	)
+	if unsafe.Sizeof(value) == 4 {
+		binary.LittleEndian.PutUint32(buf[:], math.Float32bits(*(*float32)(p)))
+		_, err := w.Write(buf[:4])
+		return err
+	}
+	binary.LittleEndian.PutUint64(buf[:], math.Float64bits(*(*float64)(p)))
+	_, err := w.Write(buf[:])
+	return err
+}
+
+func (floatCodec[T]) Decode(r ByteReader) (T, error) {
+	var (
+		value T
+		buf   [8]byte
+		p     = unsafe.Pointer(&value)
+	)
+	if unsafe.Sizeof(value) == 4 {
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return value, err
+		}
+		*(*float32)(p) = math.Float32frombits(binary.LittleEndian.Uint32(buf[:]))
+		return value, nil
+	}
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return value, err
+	}
+	*(*float64)(p) = math.Float64frombits(binary.LittleEndian.Uint64(buf[:]))
+	return value, nil
+}
+
+// stringCodec encodes the string types as their length as a uvarint followed by their bytes.
+type stringCodec[T any] struct{}
+
+func (stringCodec[T]) Encode(w *bufio.Writer, value T) error {
+	var (
+		s   = *(*string)(unsafe.Pointer(&value))
+		buf [binary.MaxVarintLen64]byte
+	)
+	if _, err := w.Write(buf[:binary.PutUvarint(buf[:], uint64(len(s)))]); err != nil {
+		return err
+	}
+	_, err := w.WriteString(s)
+	return err
+}
+
+func (stringCodec[T]) Decode(r ByteReader) (T, error) {
+	var value T
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return value, err
+	}
+	// Grow the buffer as the bytes arrive rather than trusting the length.
+	var buf []byte
+	for uint64(len(buf)) < size {
+		chunk := size - uint64(len(buf))
+		if chunk > 1<<16 {
+			chunk = 1 << 16
+		}
+		start := len(buf)
+		buf = append(buf, make([]byte, chunk)...)
+		if _, err := io.ReadFull(r, buf[start:]); err != nil {
+			return value, err
+		}
+	}
+	*(*string)(unsafe.Pointer(&value)) = string(buf)
+	return value, nil
+}
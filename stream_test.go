@@ -0,0 +1,210 @@
+package skipset
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/zhangyunhao116/fastrand"
+)
+
+func TestStream(t *testing.T) {
+	s := NewInt64()
+	values := []int64{math.MinInt64, -1, 0, 1, 300, math.MaxInt64}
+	for _, v := range values {
+		s.Add(v)
+	}
+	var buf bytes.Buffer
+	n, err := s.WriteTo(&buf)
+	if err != nil || n != int64(buf.Len()) {
+		t.Fatal("invalid WriteTo", n, err)
+	}
+	data := buf.Bytes()
+	var s2 Int64Set
+	if n, err := s2.ReadFrom(bytes.NewReader(data)); err != nil || n != int64(len(data)) {
+		t.Fatal("invalid ReadFrom", n, err)
+	}
+	checkSet[int64](t, &s2, values)
+
+	// The values are added into the skip set.
+	d := NewInt64Desc()
+	d.Add(2)
+	d.Add(0)
+	if _, err := d.ReadFrom(bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int64](t, d, []int64{math.MaxInt64, 300, 2, 1, 0, -1, math.MinInt64})
+
+	// Truncated and invalid streams.
+	for _, data := range [][]byte{data[:len(data)-1], data[:2], nil} {
+		if _, err := NewInt64().ReadFrom(bytes.NewReader(data)); err != io.ErrUnexpectedEOF {
+			t.Fatal("truncated stream should be rejected", err)
+		}
+	}
+	if _, err := NewInt32().ReadFrom(bytes.NewReader(data)); err != errStreamData {
+		t.Fatal("out of range value should be rejected", err)
+	}
+	if _, err := NewInt().ReadFrom(bytes.NewReader([]byte{2})); err != errStreamData {
+		t.Fatal("invalid stream should be rejected", err)
+	}
+
+	// Strings, floats and multisets.
+	str := NewStringDesc()
+	str.Add("")
+	str.Add("abc")
+	buf.Reset()
+	str.WriteTo(&buf)
+	str2 := NewStringDesc()
+	str2.ReadFrom(&buf)
+	checkSet[string](t, str2, []string{"abc", ""})
+
+	f := NewFloat32()
+	f.Add(float32(math.NaN()))
+	f.Add(-1.5)
+	buf.Reset()
+	f.WriteTo(&buf)
+	f2 := NewFloat32()
+	f2.ReadFrom(&buf)
+	if f2.Len() != 2 || !f2.Contains(float32(math.NaN())) || !f2.Contains(-1.5) {
+		t.Fatal("invalid float skip set")
+	}
+
+	m := NewMulti[uint8]()
+	for _, v := range []uint8{3, 1, 3, 255} {
+		m.Add(v)
+	}
+	buf.Reset()
+	m.WriteTo(&buf)
+	m2 := NewMulti[uint8]()
+	m2.Add(3)
+	m2.ReadFrom(&buf)
+	checkMultiSet(t, m2.Range, []uint8{1, 3, 255}, []int{1, 3, 1})
+
+	// Other types require a codec.
+	p := NewFunc(func(a, b streamPoint) bool {
+		return a.x < b.x || (a.x == b.x && a.y < b.y)
+	})
+	p.Add(streamPoint{1, 2})
+	p.Add(streamPoint{0, 5})
+	if _, err := p.WriteTo(&buf); err != errNoCodec {
+		t.Fatal("invalid error", err)
+	}
+	buf.Reset()
+	if _, err := p.WriteToCodec(&buf, pointCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	var p2 FuncSet[streamPoint]
+	if _, err := p2.ReadFromCodec(bytes.NewReader(buf.Bytes()), pointCodec{}); err != errNoLess {
+		t.Fatal("invalid error", err)
+	}
+	p2 = *p.newEmpty()
+	if _, err := p2.ReadFromCodec(&buf, pointCodec{}); err != nil {
+		t.Fatal(err)
+	}
+	if !EqualFunc(p, &p2) {
+		t.Fatal("invalid FuncSet")
+	}
+}
+
+type streamPoint struct{ x, y int }
+
+type pointCodec struct{}
+
+func (pointCodec) Encode(w *bufio.Writer, value streamPoint) error {
+	_, err := w.Write([]byte{byte(value.x), byte(value.y)})
+	return err
+}
+
+func (pointCodec) Decode(r ByteReader) (streamPoint, error) {
+	var b [2]byte
+	_, err := io.ReadFull(r, b[:])
+	return streamPoint{int(b[0]), int(b[1])}, err
+}
+
+func TestStreamBackToBack(t *testing.T) {
+	a, b := OfString("x", "yy"), NewInt64()
+	b.Add(-1)
+	b.Add(300)
+	var buf bytes.Buffer
+	na, err := a.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nb, err := b.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf.WriteString("tail")
+
+	// Both with an io.ByteReader and a plain io.Reader.
+	for _, r := range []io.Reader{bytes.NewReader(buf.Bytes()), struct{ io.Reader }{bytes.NewReader(buf.Bytes())}} {
+		a2, b2 := NewString(), NewInt64()
+		if n, err := a2.ReadFrom(r); err != nil || n != na {
+			t.Fatal("invalid read", n, na, err)
+		}
+		if n, err := b2.ReadFrom(r); err != nil || n != nb {
+			t.Fatal("invalid read", n, nb, err)
+		}
+		checkSet[string](t, a2, []string{"x", "yy"})
+		checkSet[int64](t, b2, []int64{-1, 300})
+		if rest, err := io.ReadAll(r); err != nil || string(rest) != "tail" {
+			t.Fatal("invalid rest", string(rest), err)
+		}
+	}
+}
+
+func TestStreamInsert(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		s := NewUint32()
+		model := make(map[uint32]bool)
+		for j := fastrand.Uint32n(100); j > 0; j-- {
+			v := fastrand.Uint32n(1000)
+			s.Add(v)
+			model[v] = true
+		}
+		// Sorted runs with duplicates.
+		var values []uint32
+		for j := fastrand.Uint32n(5); j > 0; j-- {
+			run := make([]uint32, fastrand.Uint32n(200))
+			for k := range run {
+				run[k] = fastrand.Uint32n(1000)
+			}
+			sort.Slice(run, func(i, j int) bool { return run[i] < run[j] })
+			values = append(values, run...)
+		}
+		var buf bytes.Buffer
+		writeTo[uint32](&buf, uintCodec[uint32]{}, func(f func(value uint32) bool) {
+			for _, v := range values {
+				f(v)
+			}
+		})
+		if _, err := s.ReadFrom(&buf); err != nil {
+			t.Fatal(err)
+		}
+		var expected []uint32
+		for _, v := range values {
+			model[v] = true
+		}
+		for v := range model {
+			expected = append(expected, v)
+		}
+		sort.Slice(expected, func(i, j int) bool { return expected[i] < expected[j] })
+		checkSet[uint32](t, s, expected)
+		for _, v := range expected {
+			if !s.Contains(v) {
+				t.Fatal("invalid tower", v)
+			}
+		}
+		// Every level must be sorted.
+		for l := 0; l < maxLevel; l++ {
+			for x := s.header.loadNext(l); x != nil && x.loadNext(l) != nil; x = x.loadNext(l) {
+				if x.value >= x.loadNext(l).value {
+					t.Fatal("invalid level", l)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package skipset
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+var errTextValue = errors.New("skipset: a value is empty, contains a comma or has surrounding spaces, it can't be encoded as text")
+
+// appendText appends the values passed to f by rangeFn to buf as a comma-separated list,
+// rangeFn may be nil if there is no value.
+func appendText[T any](buf []byte, rangeFn func(f func(value T) bool), format func(buf []byte, value T) []byte) []byte {
+	if rangeFn == nil {
+		return buf
+	}
+	first := true
+	rangeFn(func(value T) bool {
+		if !first {
+			buf = append(buf, ',')
+		}
+		buf, first = format(buf, value), false
+		return true
+	})
+	return buf
+}
+
+// parseText parses a comma-separated list, the spaces around the items are trimmed and the
+// empty items are skipped.
+func parseText[T any](text string, parse func(s string) (T, error)) ([]T, error) {
+	var values []T
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		v, err := parse(item)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+func appendInteger[T integer](buf []byte, value T) []byte {
+	if ^T(0) < 0 {
+		return strconv.AppendInt(buf, int64(value), 10)
+	}
+	return strconv.AppendUint(buf, uint64(value), 10)
+}
+
+func parseInteger[T integer](s string) (T, error) {
+	bitSize := int(unsafe.Sizeof(T(0))) * 8
+	if ^T(0) < 0 {
+		v, err := strconv.ParseInt(s, 10, bitSize)
+		return T(v), err
+	}
+	v, err := strconv.ParseUint(s, 10, bitSize)
+	return T(v), err
+}
+
+func appendString(buf []byte, value string) []byte {
+	return append(buf, value...)
+}
+
+// checkTextStrings returns errTextValue if one of the values passed to f by rangeFn can't be
+// decoded by parseText.
+func checkTextStrings(rangeFn func(f func(value string) bool)) error {
+	var err error
+	rangeFn(func(value string) bool {
+		if value == "" || strings.Contains(value, ",") || strings.TrimSpace(value) != value {
+			err = errTextValue
+		}
+		return err == nil
+	})
+	return err
+}
+
+func parseString(s string) (string, error) {
+	return s, nil
+}
@@ -0,0 +1,82 @@
+package skipset
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+func TestFlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	ports := NewUint32()
+	ports.Add(22)
+	tenants := NewStringDesc()
+	fs.Var(ports, "allow-ports", "allowed ports")
+	fs.Var(tenants, "tenants", "tenants")
+	if err := fs.Parse([]string{"-allow-ports=80,443", "-allow-ports", " 8080 ,,80", "-tenants=a, b"}); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[uint32](t, ports, []uint32{22, 80, 443, 8080})
+	checkSet[string](t, tenants, []string{"b", "a"})
+	if ports.String() != "22,80,443,8080" || tenants.String() != "b,a" {
+		t.Fatal("invalid String", ports.String(), tenants.String())
+	}
+
+	// Invalid values are rejected as a whole.
+	for _, arg := range []string{"-allow-ports=1,x", "-allow-ports=65536,70000,-1", "-allow-ports=4294967296"} {
+		if err := fs.Parse([]string{arg}); err == nil {
+			t.Fatal("invalid value should be rejected", arg)
+		}
+	}
+	checkSet[uint32](t, ports, []uint32{22, 80, 443, 8080})
+
+	// Zero-value skip sets, flag calls String on them to find out the default values.
+	var ids Int32Set
+	if ids.String() != "" {
+		t.Fatal("invalid String", ids.String())
+	}
+	fs.Var(&ids, "ids", "ids")
+	if err := fs.Parse([]string{"-ids=-2147483648,2147483647"}); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int32](t, &ids, []int32{-2147483648, 2147483647})
+	if err := ids.Set("2147483648"); err == nil {
+		t.Fatal("out of range value should be rejected")
+	}
+}
+
+func TestText(t *testing.T) {
+	s := NewIntDesc()
+	if err := s.UnmarshalText([]byte("3, -1,2,3,")); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int](t, s, []int{3, 2, -1})
+	text, err := s.MarshalText()
+	if err != nil || string(text) != "3,2,-1" {
+		t.Fatal("invalid MarshalText", string(text), err)
+	}
+	if err := s.UnmarshalText([]byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	checkSet[int](t, s, []int{1})
+	var z StringSet
+	if text, err := z.MarshalText(); err != nil || len(text) != 0 {
+		t.Fatal("invalid MarshalText", string(text), err)
+	}
+	if err := s.UnmarshalText([]byte("a")); err == nil {
+		t.Fatal("invalid value should be rejected")
+	}
+	checkSet[int](t, s, []int{1})
+
+	// The strings which can't round-trip are rejected.
+	for _, values := range [][]string{{"a,b", " c"}, {"a", "b "}, {""}} {
+		if text, err := OfString(values...).MarshalText(); err != errTextValue {
+			t.Fatal("invalid MarshalText", values, string(text), err)
+		}
+	}
+	text, err = OfString("b", "a c").MarshalText()
+	if err != nil || string(text) != "a c,b" {
+		t.Fatal("invalid MarshalText", string(text), err)
+	}
+}
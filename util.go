@@ -1,6 +1,8 @@
 package skipset
 
 import (
+	"sync"
+
 	"github.com/zhangyunhao116/fastrand"
 )
 
@@ -21,6 +23,12 @@ func randomLevel() int {
 	return level
 }
 
+// lengthCounter counts the lengths of the two parts of a skip set split by SplitAt, once.
+type lengthCounter struct {
+	once  sync.Once
+	count func()
+}
+
 // ordered is a constraint that permits any ordered type: any type
 // that supports the operators < <= >= >.
 type ordered interface {
@@ -29,3 +37,27 @@ type ordered interface {
 		~float32 | ~float64 | // float
 		~string
 }
+
+// integer is a constraint that permits any integer type.
+type integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | // sign
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr // unsign
+}
+
+// integerKey maps value to an unsigned key in the same order.
+func integerKey[T integer](value T) uint64 {
+	var zero T
+	if ^zero < 0 {
+		return uint64(int64(value)) ^ (1 << 63)
+	}
+	return uint64(value)
+}
+
+// integerValue is the inverse of integerKey.
+func integerValue[T integer](k uint64) T {
+	var zero T
+	if ^zero < 0 {
+		return T(int64(k ^ (1 << 63)))
+	}
+	return T(k)
+}